@@ -0,0 +1,51 @@
+// Package alert notifies operators of conditions that need attention, e.g., a stalled reporter or a deep reorg.
+// Notifications are de-duplicated and rate-limited by a Dispatcher before a Notifier sends them.
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Kind is the condition a notification is about
+type Kind string
+
+const (
+	KindStall             Kind = "stall"
+	KindReorg             Kind = "reorg"
+	KindInconsistency     Kind = "inconsistency"
+	KindLowBalance        Kind = "low-balance"
+	KindBootstrapFailures Kind = "bootstrap-failures"
+)
+
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Alert is a notification to operators
+type Alert struct {
+	Kind     Kind              `json:"kind"`
+	Severity Severity          `json:"severity"`
+	Module   string            `json:"module"`
+	Summary  string            `json:"summary"`
+	Details  map[string]string `json:"details,omitempty"`
+	Time     time.Time         `json:"time"`
+	// Key identifies the condition for de-duplication, the kind and module if empty
+	Key string `json:"-"`
+}
+
+func (a *Alert) dedupKey() string {
+	if a.Key != "" {
+		return a.Key
+	}
+	return fmt.Sprintf("%s/%s", a.Kind, a.Module)
+}
+
+// Notifier delivers notifications, e.g., to a chat or paging system
+type Notifier interface {
+	Notify(ctx context.Context, alert *Alert) error
+}
@@ -0,0 +1,332 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
+const (
+	// queueSize bounds the notifications waiting to be sent, further ones are dropped
+	queueSize = 100
+	// stallCheckInterval is how often the watched reporters are checked for stalls
+	stallCheckInterval = 10 * time.Second
+	rateWindow         = time.Hour
+)
+
+// stallWatch tracks the relay progress of a reporter
+type stallWatch struct {
+	stallAfter time.Duration
+	last       time.Time
+	alerted    bool
+}
+
+// Dispatcher de-duplicates and rate-limits notifications, and sends them in the background.
+// It is safe for concurrent use, and a nil *Dispatcher discards everything, so callers never
+// need to check whether alerting is enabled.
+type Dispatcher struct {
+	notifier Notifier
+	timeout  time.Duration
+	logger   *zap.SugaredLogger
+
+	mu sync.Mutex
+	// limits and thresholds, which change on config reloads
+	dedupWindow       time.Duration
+	maxPerHour        int
+	reorgDepth        int
+	bootstrapFailures int
+	lastSent          map[string]time.Time // by de-duplication key
+	sent              []time.Time          // send times within the last rate window
+	stalls            map[string]*stallWatch
+	// bootstrapFailed counts the failed bootstraps in a row by reporter
+	bootstrapFailed map[string]int
+
+	queue    chan *Alert
+	quit     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// New creates a dispatcher posting to the webhook of cfg. It returns a nil dispatcher if alerting is disabled.
+func New(cfg *config.AlertsConfig, parentLogger *zap.Logger) *Dispatcher {
+	if !cfg.Enabled {
+		return nil
+	}
+	return NewDispatcher(NewWebhook(cfg.WebhookURL, cfg.Timeout), cfg, parentLogger)
+}
+
+// NewDispatcher creates a dispatcher sending through the given notifier with the limits and thresholds of cfg
+func NewDispatcher(notifier Notifier, cfg *config.AlertsConfig, parentLogger *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		notifier:          notifier,
+		timeout:           cfg.Timeout,
+		dedupWindow:       cfg.DedupWindow,
+		maxPerHour:        cfg.MaxPerHour,
+		reorgDepth:        cfg.ReorgDepth,
+		bootstrapFailures: cfg.BootstrapFailures,
+		logger:            parentLogger.With(zap.String("module", "alert")).Sugar(),
+		lastSent:          make(map[string]time.Time),
+		stalls:            make(map[string]*stallWatch),
+		bootstrapFailed:   make(map[string]int),
+		queue:             make(chan *Alert, queueSize),
+		quit:              make(chan struct{}),
+	}
+}
+
+// Start sends the queued notifications and checks the watched reporters for stalls
+func (d *Dispatcher) Start() {
+	if d == nil {
+		return
+	}
+	d.wg.Add(2)
+	go d.sendLoop()
+	go d.stallLoop()
+}
+
+// Stop sends the notifications still queued and stops the dispatcher. Only the first call stops it.
+func (d *Dispatcher) Stop() {
+	if d == nil {
+		return
+	}
+	d.stopOnce.Do(func() {
+		close(d.quit)
+		d.wg.Wait()
+	})
+}
+
+// Fire queues a notification unless the same condition was notified within the de-duplication window,
+// or the rate limit is reached
+func (d *Dispatcher) Fire(alert *Alert) {
+	if d == nil {
+		return
+	}
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+
+	d.mu.Lock()
+	key := alert.dedupKey()
+	if last, ok := d.lastSent[key]; ok && alert.Time.Sub(last) < d.dedupWindow {
+		d.mu.Unlock()
+		return
+	}
+	for len(d.sent) > 0 && alert.Time.Sub(d.sent[0]) >= rateWindow {
+		d.sent = d.sent[1:]
+	}
+	if maxPerHour := d.maxPerHour; len(d.sent) >= maxPerHour {
+		d.mu.Unlock()
+		d.logger.Warnf("Dropped %s notification of %s, more than %d notifications in the last hour: %s",
+			alert.Kind, alert.Module, maxPerHour, alert.Summary)
+		return
+	}
+	d.lastSent[key] = alert.Time
+	d.sent = append(d.sent, alert.Time)
+	d.mu.Unlock()
+
+	select {
+	case d.queue <- alert:
+	default:
+		d.logger.Warnf("Dropped %s notification of %s, too many are waiting to be sent: %s", alert.Kind, alert.Module, alert.Summary)
+	}
+}
+
+func (d *Dispatcher) sendLoop() {
+	defer d.wg.Done()
+	for {
+		select {
+		case alert := <-d.queue:
+			d.send(alert)
+		case <-d.quit:
+			for {
+				select {
+				case alert := <-d.queue:
+					d.send(alert)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) send(alert *Alert) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+	if err := d.notifier.Notify(ctx, alert); err != nil {
+		d.logger.Errorf("Failed to send %s notification of %s: %v", alert.Kind, alert.Module, err)
+		return
+	}
+	d.logger.Infof("Sent %s notification of %s: %s", alert.Kind, alert.Module, alert.Summary)
+}
+
+// WatchStall notifies when the given reporter reports no relay progress for stallAfter, counting from now.
+// Watching a watched reporter again only changes stallAfter.
+func (d *Dispatcher) WatchStall(module string, stallAfter time.Duration) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if w, ok := d.stalls[module]; ok {
+		w.stallAfter = stallAfter
+		return
+	}
+	d.stalls[module] = &stallWatch{stallAfter: stallAfter, last: time.Now()}
+}
+
+// UpdateConfig applies the limits and thresholds of cfg. The stall thresholds are set by WatchStall, and the
+// webhook and its timeout are only set on creation.
+func (d *Dispatcher) UpdateConfig(cfg *config.AlertsConfig) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dedupWindow = cfg.DedupWindow
+	d.maxPerHour = cfg.MaxPerHour
+	d.reorgDepth = cfg.ReorgDepth
+	d.bootstrapFailures = cfg.BootstrapFailures
+}
+
+// Relayed records relay progress of the given reporter, i.e., headers it or anyone else relayed to Lorenzo
+func (d *Dispatcher) Relayed(module string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if w, ok := d.stalls[module]; ok {
+		w.last = time.Now()
+		w.alerted = false
+	}
+}
+
+func (d *Dispatcher) stallLoop() {
+	defer d.wg.Done()
+	ticker := time.NewTicker(stallCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.checkStalls(time.Now())
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// checkStalls notifies each stall once, until the reporter makes progress again
+func (d *Dispatcher) checkStalls(now time.Time) {
+	var stalled []*Alert
+	d.mu.Lock()
+	for module, w := range d.stalls {
+		if w.alerted || now.Sub(w.last) < w.stallAfter {
+			continue
+		}
+		w.alerted = true
+		stalled = append(stalled, &Alert{
+			Kind:     KindStall,
+			Severity: SeverityCritical,
+			Module:   module,
+			Summary:  fmt.Sprintf("no headers relayed to Lorenzo for %v", now.Sub(w.last).Round(time.Second)),
+			Details:  map[string]string{"last_relayed": w.last.UTC().Format(time.RFC3339)},
+			Time:     now,
+		})
+	}
+	d.mu.Unlock()
+	for _, alert := range stalled {
+		d.Fire(alert)
+	}
+}
+
+// Reorg notifies a reorg of the given depth, if it is at least the configured depth
+func (d *Dispatcher) Reorg(module string, depth int, forkHeight int64, newTip string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	reorgDepth := d.reorgDepth
+	d.mu.Unlock()
+	if depth < reorgDepth {
+		return
+	}
+	d.Fire(&Alert{
+		Kind:     KindReorg,
+		Severity: SeverityWarning,
+		Module:   module,
+		Summary:  fmt.Sprintf("reorg replaced %d blocks above height %d", depth, forkHeight),
+		Details: map[string]string{
+			"depth":       fmt.Sprint(depth),
+			"fork_height": fmt.Sprint(forkHeight),
+			"new_tip":     newTip,
+		},
+		Key: fmt.Sprintf("%s/%s/%s", KindReorg, module, newTip),
+	})
+}
+
+// Inconsistency notifies that the source chain disagrees with the light client on Lorenzo
+func (d *Dispatcher) Inconsistency(module string, err error) {
+	d.Fire(&Alert{
+		Kind:     KindInconsistency,
+		Severity: SeverityCritical,
+		Module:   module,
+		Summary:  err.Error(),
+	})
+}
+
+// LowBalance notifies that the balance of a key fell below a threshold
+func (d *Dispatcher) LowBalance(key, address, balance, threshold string, critical bool) {
+	severity, summary := SeverityWarning, "is below the warning balance"
+	if critical {
+		severity, summary = SeverityCritical, "is below the critical balance, the key stops broadcasting"
+	}
+	d.Fire(&Alert{
+		Kind:     KindLowBalance,
+		Severity: severity,
+		Module:   "signer",
+		Summary:  fmt.Sprintf("balance %s of key %s %s %s", balance, key, summary, threshold),
+		Details: map[string]string{
+			"key":       key,
+			"address":   address,
+			"balance":   balance,
+			"threshold": threshold,
+		},
+		Key: fmt.Sprintf("%s/%s/%s", KindLowBalance, key, severity),
+	})
+}
+
+// BootstrapFailed counts a failed bootstrap of the given reporter, and notifies once the configured number of
+// bootstraps in a row failed. The count survives restarts of the reporter, until Bootstrapped resets it.
+func (d *Dispatcher) BootstrapFailed(module string, err error) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	d.bootstrapFailed[module]++
+	failures, threshold := d.bootstrapFailed[module], d.bootstrapFailures
+	d.mu.Unlock()
+	if failures < threshold {
+		return
+	}
+	d.Fire(&Alert{
+		Kind:     KindBootstrapFailures,
+		Severity: SeverityCritical,
+		Module:   module,
+		Summary:  fmt.Sprintf("%d bootstraps in a row failed, last error: %v", failures, err),
+	})
+}
+
+// Bootstrapped resets the count of failed bootstraps of the given reporter
+func (d *Dispatcher) Bootstrapped(module string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.bootstrapFailed, module)
+}
@@ -0,0 +1,112 @@
+package alert_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/alert"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
+// receiver is a webhook recording the payloads posted to it
+type receiver struct {
+	mu       sync.Mutex
+	payloads []map[string]any
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var payload map[string]any
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil || req.Header.Get("Content-Type") != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	rc.mu.Lock()
+	rc.payloads = append(rc.payloads, payload)
+	rc.mu.Unlock()
+}
+
+// dispatch fires the alerts through a dispatcher posting to a local receiver, and returns what the receiver got
+func dispatch(t *testing.T, cfg config.AlertsConfig, fire func(d *alert.Dispatcher)) []map[string]any {
+	rc := &receiver{}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	cfg.Enabled = true
+	cfg.WebhookURL = server.URL
+	d := alert.New(&cfg, zap.NewNop())
+	d.Start()
+	fire(d)
+	// Stop sends the queued notifications before returning
+	d.Stop()
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.payloads
+}
+
+func TestDispatcherDedup(t *testing.T) {
+	payloads := dispatch(t, config.DefaultAlertsConfig(), func(d *alert.Dispatcher) {
+		d.Reorg("reporter", 4, 100, "tip-a")
+		d.Reorg("reporter", 5, 100, "tip-a") // same reorg, de-duplicated
+		d.Reorg("reporter", 1, 103, "tip-b") // too shallow
+		d.Reorg("reporter", 3, 101, "tip-c")
+	})
+	if len(payloads) != 2 {
+		t.Fatalf("expected 2 notifications, got %d: %v", len(payloads), payloads)
+	}
+
+	first := payloads[0]
+	if first["kind"] != string(alert.KindReorg) || first["severity"] != string(alert.SeverityWarning) || first["module"] != "reporter" {
+		t.Fatalf("unexpected notification: %v", first)
+	}
+	if first["text"] != "[warning] reporter: reorg replaced 4 blocks above height 100" {
+		t.Fatalf("unexpected text: %v", first["text"])
+	}
+	details, _ := first["details"].(map[string]any)
+	if details["new_tip"] != "tip-a" || details["depth"] != "4" {
+		t.Fatalf("unexpected details: %v", first["details"])
+	}
+	if payloads[1]["summary"] != "reorg replaced 3 blocks above height 101" {
+		t.Fatalf("unexpected summary: %v", payloads[1]["summary"])
+	}
+}
+
+func TestDispatcherRateLimit(t *testing.T) {
+	cfg := config.DefaultAlertsConfig()
+	cfg.MaxPerHour = 2
+	payloads := dispatch(t, cfg, func(d *alert.Dispatcher) {
+		d.LowBalance("key-1", "lrz1a", "5ulrz", "10ulrz", false)
+		d.LowBalance("key-1", "lrz1a", "1ulrz", "2ulrz", true)
+		d.Inconsistency("bnbreporter", errors.New("parent hash mismatch"))
+	})
+	if len(payloads) != 2 {
+		t.Fatalf("expected 2 notifications, got %d: %v", len(payloads), payloads)
+	}
+	if payloads[1]["severity"] != string(alert.SeverityCritical) {
+		t.Fatalf("expected the critical balance notification, got %v", payloads[1])
+	}
+}
+
+func TestDispatcherBootstrapFailures(t *testing.T) {
+	cfg := config.DefaultAlertsConfig()
+	cfg.BootstrapFailures = 2
+	payloads := dispatch(t, cfg, func(d *alert.Dispatcher) {
+		err := errors.New("Lorenzo unavailable")
+		d.BootstrapFailed("bnbreporter", err)
+		d.Bootstrapped("bnbreporter")
+		d.BootstrapFailed("bnbreporter", err)
+		d.BootstrapFailed("bnbreporter", err)
+	})
+	if len(payloads) != 1 {
+		t.Fatalf("expected 1 notification, got %d: %v", len(payloads), payloads)
+	}
+	if payloads[0]["summary"] != "2 bootstraps in a row failed, last error: Lorenzo unavailable" {
+		t.Fatalf("unexpected summary: %v", payloads[0]["summary"])
+	}
+}
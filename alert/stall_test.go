@@ -0,0 +1,62 @@
+package alert
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
+// stalls returns the modules of the stall notifications queued so far
+func stalls(d *Dispatcher) []string {
+	var modules []string
+	for {
+		select {
+		case alert := <-d.queue:
+			if alert.Kind == KindStall {
+				modules = append(modules, alert.Module)
+			}
+		default:
+			return modules
+		}
+	}
+}
+
+func TestCheckStalls(t *testing.T) {
+	cfg := config.DefaultAlertsConfig()
+	cfg.DedupWindow = 0
+	d := NewDispatcher(nil, &cfg, zap.NewNop())
+	d.WatchStall("reporter", 10*time.Minute)
+	d.WatchStall("bnbreporter", time.Minute)
+	start := time.Now()
+
+	d.checkStalls(start.Add(5 * time.Minute))
+	if got := stalls(d); len(got) != 1 || got[0] != "bnbreporter" {
+		t.Fatalf("expected only the BNB reporter to stall, got %v", got)
+	}
+
+	d.Relayed("bnbreporter")
+	d.checkStalls(start.Add(11 * time.Minute))
+	if got := stalls(d); len(got) != 2 {
+		t.Fatalf("expected both reporters to stall, got %v", got)
+	}
+	d.checkStalls(start.Add(20 * time.Minute))
+	if got := stalls(d); len(got) != 0 {
+		t.Fatalf("expected a stall to be notified once, got %v", got)
+	}
+
+	// progress re-arms the watch, and watching again only changes the threshold
+	d.Relayed("reporter")
+	d.WatchStall("reporter", time.Hour)
+	relayed := time.Now()
+	d.checkStalls(relayed.Add(30 * time.Minute))
+	if got := stalls(d); len(got) != 0 {
+		t.Fatalf("expected no stall within the new threshold, got %v", got)
+	}
+	d.checkStalls(relayed.Add(61 * time.Minute))
+	if got := stalls(d); len(got) != 1 || got[0] != "reporter" {
+		t.Fatalf("expected the re-armed reporter to stall again, got %v", got)
+	}
+}
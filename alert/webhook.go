@@ -0,0 +1,65 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Webhook POSTs every notification as JSON to a URL
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+var _ Notifier = (*Webhook)(nil)
+
+func NewWebhook(webhookURL string, timeout time.Duration) *Webhook {
+	return &Webhook{
+		url:    webhookURL,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// webhookPayload is the alert together with a text field, which chat webhooks display as the message
+type webhookPayload struct {
+	Text string `json:"text"`
+	*Alert
+}
+
+func (w *Webhook) Notify(ctx context.Context, alert *Alert) error {
+	body, err := json.Marshal(&webhookPayload{
+		Text:  fmt.Sprintf("[%s] %s: %s", alert.Severity, alert.Module, alert.Summary),
+		Alert: alert,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		// the URL may hold a token, so do not let it leak into the logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post to webhook: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64*1024))
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %s", res.Status)
+	}
+	return nil
+}
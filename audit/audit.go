@@ -0,0 +1,251 @@
+// Package audit keeps a tamper-evident log of the header txs the reporters send to Lorenzo. Entries are
+// JSON lines, each holding the hash of the previous one, so that deleting or editing an entry breaks the chain.
+package audit
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
+// maxEntrySize bounds a single audit line; an entry lists the hashes of at most one tx's headers
+const maxEntrySize = 16 * 1024 * 1024
+
+// Reason tells why headers were sent to Lorenzo
+type Reason string
+
+const (
+	// ReasonBootstrap is the submission of the headers found missing when a reporter starts
+	ReasonBootstrap Reason = "bootstrap"
+	// ReasonEvent is the submission of new blocks of the source chain
+	ReasonEvent Reason = "event"
+	// ReasonCatchUp is the submission of the headers Lorenzo lags behind the source chain by
+	ReasonCatchUp Reason = "catch-up"
+	// ReasonBackfill is the resubmission of headers held back while Lorenzo or the signers were unavailable
+	ReasonBackfill Reason = "backfill"
+)
+
+// Msg types of the audited txs
+const (
+	MsgInsertHeaders    = "InsertHeaders"
+	MsgBNBUploadHeaders = "BNBUploadHeaders"
+)
+
+type reasonKey struct{}
+
+// WithReason returns a context under which the header txs are audited with the given reason
+func WithReason(ctx context.Context, reason Reason) context.Context {
+	return context.WithValue(ctx, reasonKey{}, reason)
+}
+
+// ReasonFrom returns the reason set by WithReason, ReasonEvent if there is none
+func ReasonFrom(ctx context.Context) Reason {
+	if reason, ok := ctx.Value(reasonKey{}).(Reason); ok {
+		return reason
+	}
+	return ReasonEvent
+}
+
+// Entry is a header tx sent to Lorenzo. Seq, Time, PrevHash and Hash are set by the log.
+type Entry struct {
+	Seq         uint64    `json:"seq"`
+	Time        time.Time `json:"time"`
+	Module      string    `json:"module"`
+	Msg         string    `json:"msg"`
+	Reason      Reason    `json:"reason"`
+	FirstHeight uint64    `json:"first_height"`
+	LastHeight  uint64    `json:"last_height"`
+	Hashes      []string  `json:"hashes"`
+	Signer      string    `json:"signer"`
+	TxHash      string    `json:"tx_hash,omitempty"`
+	Code        uint32    `json:"code"`
+	GasUsed     int64     `json:"gas_used"` // 0 unless the tx was committed
+	Error       string    `json:"error,omitempty"`
+	// PrevHash is the hash of the previous entry, empty for the first one
+	PrevHash string `json:"prev_hash"`
+	// Hash is the SHA-256 of the entry encoded without it
+	Hash string `json:"hash,omitempty"`
+}
+
+// computeHash returns the hash of the entry, which covers every field but Hash
+func (e *Entry) computeHash() (string, error) {
+	unhashed := *e
+	unhashed.Hash = ""
+	data, err := json.Marshal(&unhashed)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Log appends entries to the audit log, syncing every one to disk. It is safe for concurrent use,
+// and a nil *Log discards everything, so callers never need to check whether auditing is enabled.
+type Log struct {
+	mu     sync.Mutex
+	file   *os.File
+	seq    uint64
+	prev   string
+	logger *zap.SugaredLogger
+}
+
+// New opens the audit log described by cfg and resumes its chain. It returns a nil log if auditing is disabled.
+func New(cfg *config.AuditConfig, parentLogger *zap.Logger) (*Log, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	// refuse to extend a chain that is already broken, the new entries could not be trusted either
+	last, err := verify(cfg.Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to resume audit log: %w", err)
+	}
+	file, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	l := &Log{
+		file:   file,
+		logger: parentLogger.With(zap.String("module", "audit")).Sugar(),
+	}
+	if last != nil {
+		l.seq, l.prev = last.Seq, last.Hash
+	}
+	return l, nil
+}
+
+// Record chains the entry to the previous one and appends it. Failures are logged, not returned,
+// so that auditing never stops a reporter.
+func (l *Log) Record(entry *Entry) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry.Seq = l.seq + 1
+	entry.Time = time.Now().UTC()
+	entry.PrevHash = l.prev
+	if entry.Hashes == nil {
+		entry.Hashes = []string{}
+	}
+	hash, err := entry.computeHash()
+	if err != nil {
+		l.logger.Errorf("Failed to hash audit entry: %v", err)
+		return
+	}
+	entry.Hash = hash
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		l.logger.Errorf("Failed to encode audit entry: %v", err)
+		return
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		l.logger.Errorf("Failed to write audit entry: %v", err)
+		return
+	}
+	if err := l.file.Sync(); err != nil {
+		l.logger.Errorf("Failed to sync audit log: %v", err)
+	}
+	l.seq, l.prev = entry.Seq, entry.Hash
+}
+
+// Close closes the audit log. Closing a nil log is a no-op.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Verify checks the chain of the audit log at path and returns the number of entries.
+// The error of a broken chain names the first line that does not verify.
+func Verify(path string) (int, error) {
+	last, err := verify(path)
+	if last == nil {
+		return 0, err
+	}
+	return int(last.Seq), err
+}
+
+// verify checks the chain of the audit log at path and returns its last entry, nil if it has none
+func verify(path string) (*Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var last *Entry
+	reader := bufio.NewReaderSize(f, 64*1024)
+	for line := 1; ; line++ {
+		data, err := readLine(reader)
+		if err == io.EOF {
+			return last, nil
+		}
+		if err != nil {
+			return last, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		entry := &Entry{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			return last, fmt.Errorf("line %d: invalid entry: %w", line, err)
+		}
+		hash, err := entry.computeHash()
+		if err != nil {
+			return last, fmt.Errorf("line %d: %w", line, err)
+		}
+		switch {
+		case entry.Hash != hash:
+			return last, fmt.Errorf("line %d: entry does not match its hash", line)
+		case last == nil && (entry.Seq != 1 || entry.PrevHash != ""):
+			return last, fmt.Errorf("line %d: log does not start with the first entry (seq %d)", line, entry.Seq)
+		case last != nil && (entry.Seq != last.Seq+1 || entry.PrevHash != last.Hash):
+			return last, fmt.Errorf("line %d: entry %d does not follow entry %d", line, entry.Seq, last.Seq)
+		}
+		last = entry
+	}
+}
+
+// readLine reads a line without its newline. A last line without newline, e.g., of a write cut
+// short by a crash, is an error since the entry may be incomplete.
+func readLine(reader *bufio.Reader) ([]byte, error) {
+	var data []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		data = append(data, chunk...)
+		if len(data) > maxEntrySize {
+			return nil, fmt.Errorf("entry exceeds %d bytes", maxEntrySize)
+		}
+		switch {
+		case err == nil:
+			return data[:len(data)-1], nil
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case err == io.EOF && len(data) > 0:
+			return nil, errors.New("truncated entry")
+		default:
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package audit_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/audit"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
+func writeLog(t *testing.T, path string, entries int) {
+	l, err := audit.New(&config.AuditConfig{Enabled: true, Path: path}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < entries; i++ {
+		l.Record(&audit.Entry{
+			Module:      "reporter",
+			Msg:         audit.MsgInsertHeaders,
+			Reason:      audit.ReasonEvent,
+			FirstHeight: uint64(100 + i),
+			LastHeight:  uint64(100 + i),
+			Hashes:      []string{strings.Repeat("ab", 32)},
+			Signer:      "lrz1signer",
+			TxHash:      strings.Repeat("CD", 32),
+			GasUsed:     90000,
+		})
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	writeLog(t, path, 2)
+	// reopening resumes the chain
+	writeLog(t, path, 1)
+
+	entries, err := audit.Verify(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries != 3 {
+		t.Fatalf("expected 3 entries, got %d", entries)
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	writeLog(t, path, 3)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+
+	tests := map[string]string{
+		"edited":        lines[0] + strings.Replace(lines[1], `"gas_used":90000`, `"gas_used":9000`, 1) + lines[2],
+		"deleted":       lines[0] + lines[2],
+		"first deleted": lines[1] + lines[2],
+	}
+	for name, tampered := range tests {
+		if err := os.WriteFile(path, []byte(tampered), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := audit.Verify(path); err == nil || !strings.Contains(err.Error(), "line ") {
+			t.Fatalf("%s: expected a broken chain, got %v", name, err)
+		}
+		// the reporters refuse to extend a broken chain
+		if _, err := audit.New(&config.AuditConfig{Enabled: true, Path: path}, zap.NewNop()); err == nil {
+			t.Fatalf("%s: expected the log not to open", name)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package bnbclient
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -17,9 +18,11 @@ type Client struct {
 	ethClient *ethclient.Client
 	// Supplement to ethclient
 	rpcClient *rpc.Client
+	// timeout is the deadline of every RPC call, none if 0
+	timeout time.Duration
 }
 
-func New(rpcUrl string) (*Client, error) {
+func New(rpcUrl string, timeout time.Duration) (*Client, error) {
 	client, err := ethclient.Dial(rpcUrl)
 	if err != nil {
 		return nil, err
@@ -33,23 +36,38 @@ func New(rpcUrl string) (*Client, error) {
 	return &Client{
 		ethClient: client,
 		rpcClient: rpcClient,
+		timeout:   timeout,
 	}, nil
 }
 
-func (c *Client) LatestHeader() (*bnbtypes.Header, error) {
-	latestBlockNumber, err := c.BlockNumber()
+// Close closes the connections to the BNB node
+func (c *Client) Close() {
+	c.ethClient.Close()
+	c.rpcClient.Close()
+}
+
+// callContext bounds an RPC call by the given context and the per-call deadline
+func (c *Client) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+func (c *Client) LatestHeader(ctx context.Context) (*bnbtypes.Header, error) {
+	latestBlockNumber, err := c.BlockNumber(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.HeaderByNumber(latestBlockNumber)
+	return c.HeaderByNumber(ctx, latestBlockNumber)
 }
 
-func (c *Client) RangeHeaders(start, end uint64) ([]*bnbtypes.Header, error) {
+func (c *Client) RangeHeaders(ctx context.Context, start, end uint64) ([]*bnbtypes.Header, error) {
 	if start > end {
 		return nil, errors.New("start block number should be less than end block number")
 	}
-	endHeader, err := c.HeaderByNumber(end)
+	endHeader, err := c.HeaderByNumber(ctx, end)
 	if err != nil {
 		return nil, err
 	}
@@ -58,7 +76,7 @@ func (c *Client) RangeHeaders(start, end uint64) ([]*bnbtypes.Header, error) {
 	preHeaderHash := endHeader.ParentHash
 
 	for i := len(chainHeaders) - 2; i >= 0; i-- {
-		header, err := c.HeaderByHash(preHeaderHash)
+		header, err := c.HeaderByHash(ctx, preHeaderHash)
 		if err != nil {
 			return nil, err
 		}
@@ -69,9 +87,12 @@ func (c *Client) RangeHeaders(start, end uint64) ([]*bnbtypes.Header, error) {
 	return chainHeaders, nil
 }
 
-func (c *Client) HeaderByNumber(number uint64) (*bnbtypes.Header, error) {
+func (c *Client) HeaderByNumber(ctx context.Context, number uint64) (*bnbtypes.Header, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
 	var header *bnbtypes.Header
-	err := c.rpcClient.CallContext(context.Background(), &header, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false)
+	err := c.rpcClient.CallContext(ctx, &header, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false)
 	if err == nil && header == nil {
 		err = ethereum.NotFound
 	}
@@ -79,9 +100,12 @@ func (c *Client) HeaderByNumber(number uint64) (*bnbtypes.Header, error) {
 	return header, err
 }
 
-func (c *Client) HeaderByHash(hash common.Hash) (*bnbtypes.Header, error) {
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (*bnbtypes.Header, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
 	var header *bnbtypes.Header
-	err := c.rpcClient.CallContext(context.Background(), &header, "eth_getBlockByHash", hash, false)
+	err := c.rpcClient.CallContext(ctx, &header, "eth_getBlockByHash", hash, false)
 	if err == nil && header == nil {
 		err = ethereum.NotFound
 	}
@@ -89,8 +113,11 @@ func (c *Client) HeaderByHash(hash common.Hash) (*bnbtypes.Header, error) {
 	return header, err
 }
 
-func (c *Client) BlockNumber() (uint64, error) {
-	blockNumber, err := c.ethClient.BlockNumber(context.Background())
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
+	blockNumber, err := c.ethClient.BlockNumber(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,58 @@
+package bnbclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbclient"
+)
+
+// hungNode accepts RPC calls but never answers them until the test ends
+func hungNode(t *testing.T) string {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(func() {
+		close(done)
+		srv.Close()
+	})
+	return srv.URL
+}
+
+func TestCallDeadline(t *testing.T) {
+	client, err := bnbclient.New(hungNode(t), 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, err = client.BlockNumber(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the per-call deadline to pass, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("call returned after %v", elapsed)
+	}
+}
+
+func TestCallCanceled(t *testing.T) {
+	client, err := bnbclient.New(hungNode(t), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err = client.HeaderByNumber(ctx, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the call to be canceled, got %v", err)
+	}
+}
@@ -1,15 +1,20 @@
 package bnbclient
 
 import (
+	"context"
+
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbclient/bnbtypes"
 )
 
+// BNBClient queries the BNB node. Every RPC call gives up once the given context is done or the per-call
+// deadline of the client passes.
 type BNBClient interface {
-	BlockNumber() (uint64, error)
-	LatestHeader() (*bnbtypes.Header, error)
-	RangeHeaders(start, end uint64) ([]*bnbtypes.Header, error)
-	HeaderByNumber(number uint64) (*bnbtypes.Header, error)
-	HeaderByHash(hash common.Hash) (*bnbtypes.Header, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	LatestHeader(ctx context.Context) (*bnbtypes.Header, error)
+	RangeHeaders(ctx context.Context, start, end uint64) ([]*bnbtypes.Header, error)
+	HeaderByNumber(ctx context.Context, number uint64) (*bnbtypes.Header, error)
+	HeaderByHash(ctx context.Context, hash common.Hash) (*bnbtypes.Header, error)
+	Close()
 }
@@ -0,0 +1,45 @@
+package bnbreporter
+
+import (
+	"context"
+
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/audit"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbclient/bnbtypes"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+)
+
+// auditUploadHeaders records a BNBUploadHeaders call to the audit log
+func (r *BNBReporter) auditUploadHeaders(ctx context.Context, headers []*bnbtypes.Header, signer string, res *pv.RelayerTxResponse, err error) {
+	if r.audit == nil {
+		return
+	}
+	entry := &audit.Entry{
+		Module:      journal.ModuleBNBReporter,
+		Msg:         audit.MsgBNBUploadHeaders,
+		Reason:      audit.ReasonFrom(ctx),
+		FirstHeight: headers[0].Number.Uint64(),
+		LastHeight:  headers[len(headers)-1].Number.Uint64(),
+		Hashes:      make([]string, 0, len(headers)),
+		Signer:      signer,
+	}
+	for _, header := range headers {
+		entry.Hashes = append(entry.Hashes, header.Hash().Hex())
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if res != nil {
+		entry.TxHash, entry.Code = res.TxHash, res.Code
+		// uncommitted txs have no height and no gas used
+		if res.TxHash != "" && res.Height > 0 {
+			gasUsed, gasErr := r.lorenzoClient.TxGasUsed(ctx, res.TxHash)
+			if gasErr != nil {
+				r.logger.Warnf("Failed to query gas used by tx %s: %v", res.TxHash, gasErr)
+			}
+			entry.GasUsed = gasUsed
+		}
+	}
+	r.audit.Record(entry)
+}
@@ -2,56 +2,103 @@ package bnbreporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
-	"github.com/Lorenzo-Protocol/lorenzo/v3/x/bnblightclient/types"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbclient/bnbtypes"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/tracing"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
 func (r *BNBReporter) mainLoop() {
 	r.logger.Infof("=======BNB reporter start syncer=========")
 
 	networkErrorTimeSleep := time.Millisecond * 300
-	blockSleepTime := time.Second
+	paused := false
+	var waitingSince time.Time // when the next header was first found immature
 	for {
 		select {
 		case <-r.quit:
 			return
 		default:
 		}
+		r.checkStall(paused)
 
-		bnbTip, err := r.client.LatestHeader()
+		delayBlocks := r.delayBlocks.Load()
+		blockSleepTime := time.Duration(r.pollInterval.Load())
+		polled := time.Now()
+		bnbTip, err := r.client.LatestHeader(r.ctx)
 		if err != nil {
 			r.logger.Errorf("failed to get BNB current height: %v", err)
 			time.Sleep(networkErrorTimeSleep)
 			continue
 		}
+		r.watchdog.sourceTip = bnbTip.Number.Uint64()
+		r.recordTips(bnbTip)
+
+		// keep following the BNB tip, but do not broadcast while the signers are out of funds
+		if r.lorenzoClient.Paused() {
+			if !paused {
+				paused = true
+				r.logger.Warnf("Lorenzo signers are paused for low funds, holding back header uploads. BNB tip: %d", bnbTip.Number.Uint64())
+			}
+			r.logger.Debugw("Paused", "lorenzoTip", r.lorenzoTip.Number.Uint64(), "bnbTip", bnbTip.Number.Uint64())
+			time.Sleep(blockSleepTime)
+			continue
+		}
+		if paused {
+			paused = false
+			r.logger.Infof("Lorenzo signers resumed, uploading headers from %d", r.lorenzoTip.Number.Uint64()+1)
+		}
 
-		if r.delayBlocks+r.lorenzoTip.Number.Uint64()+1 > bnbTip.Number.Uint64() {
-			r.logger.Debugf("delay blocks: %d, lorenzoTip: %d, bnbTip: %d",
-				r.delayBlocks, r.lorenzoTip.Number.Uint64(), bnbTip.Number.Uint64())
+		if delayBlocks+r.lorenzoTip.Number.Uint64()+1 > bnbTip.Number.Uint64() {
+			r.logger.Debugw("Waiting for mature BNB blocks",
+				"delayBlocks", delayBlocks, "lorenzoTip", r.lorenzoTip.Number.Uint64(), "bnbTip", bnbTip.Number.Uint64())
+			if waitingSince.IsZero() {
+				waitingSince = polled
+			}
 			time.Sleep(blockSleepTime)
 			continue
 		}
+		ctx, span := r.startRelaySpan(bnbTip, polled, waitingSince)
+		waitingSince = time.Time{}
 
 		start := r.lorenzoTip.Number.Uint64() + 1
-		end := bnbTip.Number.Uint64() - r.delayBlocks
+		end := bnbTip.Number.Uint64() - delayBlocks
 		if end-start+1 > FetchBNBHeaderBatchSize {
 			end = start + FetchBNBHeaderBatchSize - 1
 		}
 
-		newHeaders, err := r.client.RangeHeaders(start, end)
+		fetchCtx, fetchSpan := tracer.Start(ctx, "bnbreporter.FetchHeaders", trace.WithAttributes(
+			tracing.FirstHeightKey.Int64(int64(start)),
+			tracing.LastHeightKey.Int64(int64(end)),
+		))
+		newHeaders, err := r.client.RangeHeaders(fetchCtx, start, end)
+		tracing.End(fetchSpan, err)
 		if err != nil {
+			tracing.End(span, err)
 			r.logger.Errorf("failed to get BNB headers: %v", err)
 			time.Sleep(networkErrorTimeSleep)
 			continue
 		}
-		if err := r.handleHeaders(newHeaders); err != nil {
+		err = r.handleHeaders(ctx, newHeaders)
+		tracing.End(span, err)
+		r.watchdog.lorenzoDown = errors.Is(err, types.ErrLorenzoUnavailable)
+		if err != nil {
 			r.logger.Warnf("failed to handle headers: %v", err)
+			if errors.Is(err, types.ErrChainInconsistency) {
+				r.alerts.Inconsistency(journal.ModuleBNBReporter, err)
+			}
 			if err := r.boostrap(); err != nil {
 				r.logger.Errorf("failed to bootstrap: %v", err)
+				r.watchdog.lorenzoDown = r.watchdog.lorenzoDown || errors.Is(err, types.ErrLorenzoUnavailable)
+				r.alerts.BootstrapFailed(journal.ModuleBNBReporter, err)
+			} else {
+				r.alerts.Bootstrapped(journal.ModuleBNBReporter)
 			}
 
 			time.Sleep(networkErrorTimeSleep)
@@ -60,10 +107,34 @@ func (r *BNBReporter) mainLoop() {
 
 		// update lorenzoTip after successfully handling the header
 		r.lorenzoTip = newHeaders[len(newHeaders)-1]
+		r.recordTips(bnbTip)
 	}
 }
 
-func (r *BNBReporter) handleHeader(newHeader *bnbtypes.Header) error {
+// startRelaySpan starts the span of relaying the mature headers found by the tip poll at the given time.
+// Its first children record the poll and, if the headers had to mature, the wait since waitingSince.
+func (r *BNBReporter) startRelaySpan(bnbTip *bnbtypes.Header, polled, waitingSince time.Time) (context.Context, trace.Span) {
+	start := polled
+	if !waitingSince.IsZero() {
+		start = waitingSince
+	}
+	ctx, span := tracer.Start(r.ctx, "bnbreporter.RelayHeaders", trace.WithTimestamp(start), trace.WithAttributes(
+		tracing.TipKey.Int64(bnbTip.Number.Int64()),
+		tracing.HashKey.String(bnbTip.Hash().Hex()),
+	))
+	if !waitingSince.IsZero() {
+		_, wait := tracer.Start(ctx, "bnbreporter.WaitMaturity", trace.WithTimestamp(waitingSince))
+		wait.End(trace.WithTimestamp(polled))
+	}
+	_, poll := tracer.Start(ctx, "bnbreporter.FetchTip", trace.WithTimestamp(polled), trace.WithAttributes(
+		tracing.HeightKey.Int64(bnbTip.Number.Int64()),
+		tracing.HashKey.String(bnbTip.Hash().Hex()),
+	))
+	poll.End()
+	return ctx, span
+}
+
+func (r *BNBReporter) handleHeader(ctx context.Context, newHeader *bnbtypes.Header) error {
 	if newHeader == nil {
 		return nil
 	}
@@ -73,19 +144,15 @@ func (r *BNBReporter) handleHeader(newHeader *bnbtypes.Header) error {
 		return fmt.Errorf("newHeader number %d is not the next block of lorenzoTip number %d", newHeader.Number.Uint64(), r.lorenzoTip.Number.Uint64())
 	}
 	if r.lorenzoTip.Hash() != newHeader.ParentHash {
-		err := fmt.Errorf("BNB chain is inconsistent with Lorenzo chain: k-deep(%d) block in Lorenzo header chain: %s", r.delayBlocks, newHeader.Hash().Hex())
+		err := fmt.Errorf("%w: BNB chain is inconsistent with Lorenzo chain: k-deep(%d) block in Lorenzo header chain: %s",
+			types.ErrChainInconsistency, r.delayBlocks.Load(), newHeader.Hash().Hex())
 		return err
 	}
-
-	lorenzoBNBHeaders, err := ConvertBNBHeaderToLorenzoBNBHeaders([]*bnbtypes.Header{newHeader})
-	if err != nil {
+	if err := r.checkpoints.check(newHeader); err != nil {
 		return err
 	}
-	_, err = r.lorenzoClient.BNBUploadHeaders(context.Background(), &types.MsgUploadHeaders{
-		Signer:  r.lorenzoClient.MustGetAddr(),
-		Headers: lorenzoBNBHeaders,
-	})
-	if err != nil {
+
+	if err := r.uploadHeaders(ctx, []*bnbtypes.Header{newHeader}); err != nil {
 		return err
 	}
 
@@ -94,7 +161,7 @@ func (r *BNBReporter) handleHeader(newHeader *bnbtypes.Header) error {
 	return nil
 }
 
-func (r *BNBReporter) handleHeaders(newHeaders []*bnbtypes.Header) error {
+func (r *BNBReporter) handleHeaders(ctx context.Context, newHeaders []*bnbtypes.Header) error {
 	if len(newHeaders) == 0 {
 		return nil
 	}
@@ -107,19 +174,16 @@ func (r *BNBReporter) handleHeaders(newHeaders []*bnbtypes.Header) error {
 		return fmt.Errorf("newHeader number %d is not the next block of lorenzoTip number %d", newHeaders[0].Number.Uint64(), r.lorenzoTip.Number.Uint64())
 	}
 	if newHeaders[0].ParentHash != r.lorenzoTip.Hash() {
-		err := fmt.Errorf("BNB chain is inconsistent with Lorenzo chain: k-deep(%d) block in Lorenzo header chain: %s", r.delayBlocks, newHeaders[0].Hash().Hex())
+		err := fmt.Errorf("%w: BNB chain is inconsistent with Lorenzo chain: k-deep(%d) block in Lorenzo header chain: %s",
+			types.ErrChainInconsistency, r.delayBlocks.Load(), newHeaders[0].Hash().Hex())
 		return err
 	}
-
-	lorenzoBNBHeaders, err := ConvertBNBHeaderToLorenzoBNBHeaders(newHeaders)
-	if err != nil {
+	// refuse to relay headers that contradict a trusted checkpoint
+	if err := r.checkHeadersAgainstCheckpoints(newHeaders); err != nil {
 		return err
 	}
-	_, err = r.lorenzoClient.BNBUploadHeaders(context.Background(), &types.MsgUploadHeaders{
-		Signer:  r.lorenzoClient.MustGetAddr(),
-		Headers: lorenzoBNBHeaders,
-	})
-	if err != nil {
+
+	if err := r.uploadHeaders(ctx, newHeaders); err != nil {
 		return err
 	}
 
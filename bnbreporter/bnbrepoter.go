@@ -1,82 +1,181 @@
 package bnbreporter
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/alert"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/audit"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbclient"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbclient/bnbtypes"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/tracing"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
-const DefaultBNBDelayBlocks = 15
+const (
+	DefaultBNBDelayBlocks  = 15
+	DefaultBNBPollInterval = time.Second
+)
+
+var tracer = tracing.Tracer("bnbreporter")
+
+// PausedCheckInterval is how often a paused reporter checks whether its signers resumed
+const PausedCheckInterval = 30 * time.Second
 
 type BNBReporter struct {
 	cfg           *config.BNBReporterConfig
 	logger        *zap.SugaredLogger
-	delayBlocks   uint64
+	delayBlocks   atomic.Uint64 // updated on config reload
+	pollInterval  atomic.Int64  // time.Duration, updated on config reload
+	stallAfter    atomic.Int64  // time.Duration, updated on config reload
 	lorenzoClient LorenzoClient
-	client        bnbclient.BNBClient
-
+	client        bnbclient.BNBClient // replaced by the watchdog, only used by the main loop once started
+	checkpoints   checkpoints
+	metrics       *metrics.BNBReporterMetrics
+	journal       *journal.Journal
+	audit         *audit.Log
+	alerts        *alert.Dispatcher
+
+	onFailure  func(error) // handles panics of the main loop instead of crashing, if set
 	wg         sync.WaitGroup
 	quit       chan struct{}
+	ctx        context.Context // canceled by Stop, so that no RPC call outlives the reporter
+	cancel     context.CancelFunc
 	lorenzoTip *bnbtypes.Header // Last BNB BlockNumber reported to Lorenzo
+	watchdog   watchdogState
 }
 
-func New(parentLogger *zap.Logger, lorenzoClient LorenzoClient, cfg *config.BNBReporterConfig) (*BNBReporter, error) {
+func New(parentLogger *zap.Logger, lorenzoClient LorenzoClient, cfg *config.BNBReporterConfig, metrics *metrics.BNBReporterMetrics,
+	journal *journal.Journal, audit *audit.Log, alerts *alert.Dispatcher) (*BNBReporter, error) {
 	logger := parentLogger.With(zap.String("module", "BNB-reporter")).Sugar()
 
-	client, err := bnbclient.New(cfg.RpcUrl)
+	r := &BNBReporter{
+		cfg:           cfg,
+		logger:        logger,
+		lorenzoClient: lorenzoClient,
+		checkpoints:   newCheckpoints(cfg.Checkpoints),
+		metrics:       metrics,
+		journal:       journal,
+		audit:         audit,
+		alerts:        alerts,
+		quit:          make(chan struct{}),
+	}
+	client, err := r.newClient()
 	if err != nil {
 		return nil, err
 	}
+	r.client = client
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	r.UpdateConfig(cfg)
+	return r, nil
+}
 
-	if cfg.DelayBlocks == 0 {
-		cfg.DelayBlocks = DefaultBNBDelayBlocks
+// UpdateConfig applies the settings of the given config that can change at runtime: delay blocks, poll interval
+// and the stall threshold of the watchdog
+func (r *BNBReporter) UpdateConfig(cfg *config.BNBReporterConfig) {
+	delayBlocks := cfg.DelayBlocks
+	if delayBlocks == 0 {
+		delayBlocks = DefaultBNBDelayBlocks
+	}
+	pollInterval := cfg.PollInterval
+	if pollInterval == 0 {
+		pollInterval = DefaultBNBPollInterval
 	}
 
-	return &BNBReporter{
-		cfg:           cfg,
-		logger:        logger,
-		delayBlocks:   cfg.DelayBlocks,
-		lorenzoClient: lorenzoClient,
-		client:        client,
-		quit:          make(chan struct{}),
-	}, nil
+	if old := r.delayBlocks.Swap(delayBlocks); old != 0 && old != delayBlocks {
+		r.logger.Infof("Delay blocks changed from %d to %d", old, delayBlocks)
+	}
+	if old := time.Duration(r.pollInterval.Swap(int64(pollInterval))); old != 0 && old != pollInterval {
+		r.logger.Infof("Poll interval changed from %v to %v", old, pollInterval)
+	}
+	if old := time.Duration(r.stallAfter.Swap(int64(cfg.StallAfter))); old != 0 && old != cfg.StallAfter {
+		r.logger.Infof("Stall after changed from %v to %v", old, cfg.StallAfter)
+	}
+}
+
+// SetFailureHandler makes the reporter hand panics of its main loop to the given handler instead of crashing
+// the process, so that other components of the process keep running. It has to be called before Start.
+func (r *BNBReporter) SetFailureHandler(handler func(error)) {
+	r.onFailure = handler
+}
+
+func (r *BNBReporter) recoverFailure() {
+	if r.onFailure == nil {
+		return
+	}
+	if rec := recover(); rec != nil {
+		err := types.PanicError(rec)
+		if r.ctx.Err() != nil {
+			r.logger.Infof("Failed while stopping: %v", err)
+			return
+		}
+		if errors.Is(err, types.ErrChainInconsistency) {
+			r.alerts.Inconsistency(journal.ModuleBNBReporter, err)
+		}
+		r.onFailure(err)
+	}
 }
 
-func (r *BNBReporter) Start() {
+// Start bootstraps the reporter and starts its main loop. It returns the failure of the bootstrap and the
+// catch up, unless it is due to Stop.
+func (r *BNBReporter) Start() error {
 	select {
 	case <-r.quit:
 		r.logger.Info("BNB reporter already stopped")
-		return
+		return nil
 	default:
 	}
 
 	if err := r.boostrap(); err != nil {
-		panic(err)
+		if r.stopped(err) {
+			return nil
+		}
+		r.alerts.BootstrapFailed(journal.ModuleBNBReporter, err)
+		return err
 	}
+	r.alerts.Bootstrapped(journal.ModuleBNBReporter)
 
 	if err := r.WaitLorenzoCatchUp(); err != nil {
-		panic(err)
+		if r.stopped(err) {
+			return nil
+		}
+		return err
 	}
 	if err := r.WaitBNBCatchUp(); err != nil {
-		panic(err)
+		if r.stopped(err) {
+			return nil
+		}
+		return err
 	}
 
 	// Start the reporter
 	r.wg.Add(1)
 	go func() {
 		defer r.wg.Done()
+		defer r.recoverFailure()
 		r.mainLoop()
 	}()
+	return nil
+}
+
+// stopped tells whether the given error is due to the reporter stopping, e.g., an RPC call canceled by Stop
+func (r *BNBReporter) stopped(err error) bool {
+	return errors.Is(err, errBNBReporterStopped) || r.ctx.Err() != nil
 }
 
 func (r *BNBReporter) Stop() {
 	select {
 	case <-r.quit:
 	default:
+		r.cancel()
 		close(r.quit)
 	}
 }
@@ -1,32 +1,37 @@
 package bnbreporter
 
 import (
-	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
-	"github.com/Lorenzo-Protocol/lorenzo/v3/x/bnblightclient/types"
-
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/audit"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbclient/bnbtypes"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
 var errLatestBNBHeaderNotFound = errors.New("latested header not found")
 
+var errBNBReporterStopped = errors.New("BNB reporter stopped")
+
 const baseBNBHeaderHeightDepth = 100
 
 const FetchBNBHeaderBatchSize = 100
 
 func (r *BNBReporter) boostrap() error {
-	lorenzoBNBHeader, err := r.lorenzoClient.BNBLatestHeader()
+	// refuse to bootstrap from a BNB node that disagrees with any trusted checkpoint
+	if err := r.verifyCheckpoints(); err != nil {
+		return err
+	}
+
+	lorenzoBNBHeader, err := r.queryLorenzoTip()
 	if err != nil {
 		if strings.Contains(err.Error(), errLatestBNBHeaderNotFound.Error()) {
-			if err := r.initLorenzoBNBBaseHeader(); err != nil {
-				return err
-			}
-		} else {
-			return err
+			// initLorenzoBNBBaseHeader bootstraps again once the base header is uploaded
+			return r.initLorenzoBNBBaseHeader()
 		}
+		return fmt.Errorf("failed to get Lorenzo BNB tip: %w: %w", types.ErrLorenzoUnavailable, err)
 	}
 
 	bnbHeader, err := ConvertLorenzoBNBResponseToHeader(lorenzoBNBHeader)
@@ -35,25 +40,25 @@ func (r *BNBReporter) boostrap() error {
 	}
 
 	r.lorenzoTip = bnbHeader
+	r.metrics.BootstrapsCounter.Inc()
+	r.metrics.LorenzoTipGauge.Set(float64(bnbHeader.Number.Uint64()))
 	return nil
 }
 
 func (r *BNBReporter) initLorenzoBNBBaseHeader() error {
-	baseHeader, err := r.client.HeaderByNumber(r.cfg.BaseHeight)
+	baseHeader, err := r.client.HeaderByNumber(r.ctx, r.cfg.BaseHeight)
 	if err != nil {
+		return fmt.Errorf("failed to get BNB base header %d: %w: %w", r.cfg.BaseHeight, types.ErrSourceChainUnavailable, err)
+	}
+	if err := r.checkpoints.check(baseHeader); err != nil {
 		return err
 	}
 
 	// upload baseHeader to Lorenzo
-	lorenzoBNBHeaders, err := ConvertBNBHeaderToLorenzoBNBHeaders([]*bnbtypes.Header{baseHeader})
-	if err != nil {
-		return err
+	if !r.waitUntilUnpaused() {
+		return errBNBReporterStopped
 	}
-	_, err = r.lorenzoClient.BNBUploadHeaders(context.Background(), &types.MsgUploadHeaders{
-		Signer:  r.lorenzoClient.MustGetAddr(),
-		Headers: lorenzoBNBHeaders,
-	})
-	if err != nil {
+	if err := r.uploadHeaders(audit.WithReason(r.ctx, audit.ReasonBootstrap), []*bnbtypes.Header{baseHeader}); err != nil {
 		return err
 	}
 	r.logger.Infof("uploaded base BNB header to lorenzo,height: %d, hash:%s",
@@ -63,9 +68,9 @@ func (r *BNBReporter) initLorenzoBNBBaseHeader() error {
 }
 
 func (r *BNBReporter) WaitBNBCatchUp() error {
-	bnbTipNumber, err := r.client.BlockNumber()
+	bnbTipNumber, err := r.client.BlockNumber(r.ctx)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get BNB tip: %w: %w", types.ErrSourceChainUnavailable, err)
 	}
 	if bnbTipNumber > r.lorenzoTip.Number.Uint64() {
 		return nil
@@ -77,9 +82,9 @@ func (r *BNBReporter) WaitBNBCatchUp() error {
 
 	ticker := time.NewTicker(time.Second)
 	for range ticker.C {
-		bnbTipNumber, err := r.client.BlockNumber()
+		bnbTipNumber, err := r.client.BlockNumber(r.ctx)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to get BNB tip: %w: %w", types.ErrSourceChainUnavailable, err)
 		}
 		if bnbTipNumber > r.lorenzoTip.Number.Uint64() {
 			break
@@ -89,20 +94,24 @@ func (r *BNBReporter) WaitBNBCatchUp() error {
 }
 
 func (r *BNBReporter) WaitLorenzoCatchUp() error {
-	bnbTip, err := r.client.LatestHeader()
+	bnbTip, err := r.client.LatestHeader(r.ctx)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get BNB tip: %w: %w", types.ErrSourceChainUnavailable, err)
 	}
-	if r.lorenzoTip.Number.Uint64()+r.delayBlocks >= bnbTip.Number.Uint64() {
+	delayBlocks := r.delayBlocks.Load()
+	if r.lorenzoTip.Number.Uint64()+delayBlocks >= bnbTip.Number.Uint64() {
 		return nil
 	}
-	catchUpToNumber := bnbTip.Number.Uint64() - r.delayBlocks
+	catchUpToNumber := bnbTip.Number.Uint64() - delayBlocks
 	defer func(starTime time.Time) {
 		r.logger.Infof("Wait Lorenzo tip: %d catch up to BNB  close tip: %d, BNB tip:%d, time used: %v",
 			r.lorenzoTip.Number.Uint64(), catchUpToNumber, bnbTip.Number.Uint64(), time.Since(starTime))
 	}(time.Now())
 
 	batchHeaderCh := make(chan []*bnbtypes.Header, 10)
+	// stops the fetching when the catch up returns early
+	done := make(chan struct{})
+	defer close(done)
 	go func() {
 		defer close(batchHeaderCh)
 		for i := r.lorenzoTip.Number.Uint64() + 1; i <= catchUpToNumber; i += FetchBNBHeaderBatchSize {
@@ -116,12 +125,16 @@ func (r *BNBReporter) WaitLorenzoCatchUp() error {
 			if end > catchUpToNumber {
 				end = catchUpToNumber
 			}
-			headers, err := r.client.RangeHeaders(i, end)
+			headers, err := r.client.RangeHeaders(r.ctx, i, end)
 			if err != nil {
 				r.logger.Warnf("failed to get BNB headers from %d to %d: %v", i, end, err)
 				return
 			}
-			batchHeaderCh <- headers
+			select {
+			case batchHeaderCh <- headers:
+			case <-done:
+				return
+			}
 			time.Sleep(time.Second)
 		}
 	}()
@@ -136,8 +149,11 @@ func (r *BNBReporter) WaitLorenzoCatchUp() error {
 		if !ok && len(headers) == 0 {
 			break
 		}
-		if err := r.handleHeaders(headers); err != nil {
-			panic(err)
+		if !r.waitUntilUnpaused() {
+			return nil
+		}
+		if err := r.handleHeaders(audit.WithReason(r.ctx, audit.ReasonCatchUp), headers); err != nil {
+			return fmt.Errorf("failed to submit headers to catch up with BNB: %w", err)
 		}
 	}
 
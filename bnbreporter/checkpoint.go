@@ -0,0 +1,85 @@
+package bnbreporter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbclient/bnbtypes"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// checkpoints maps BNB block numbers to the block hashes trusted at those numbers
+type checkpoints map[uint64]common.Hash
+
+func newCheckpoints(cfgs []config.CheckpointConfig) checkpoints {
+	cps := make(checkpoints, len(cfgs))
+	for _, cp := range cfgs {
+		cps[cp.Height] = common.HexToHash(cp.Hash)
+	}
+	return cps
+}
+
+// numbers returns the checkpoint block numbers in ascending order
+func (cps checkpoints) numbers() []uint64 {
+	numbers := make([]uint64, 0, len(cps))
+	for n := range cps {
+		numbers = append(numbers, n)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	return numbers
+}
+
+// check returns an error if the given header contradicts the checkpoint at its block number
+func (cps checkpoints) check(header *bnbtypes.Header) error {
+	number := header.Number.Uint64()
+	expected, ok := cps[number]
+	if !ok {
+		return nil
+	}
+	if hash := header.Hash(); hash != expected {
+		return fmt.Errorf("%w: BNB block %d is %s, expected %s", types.ErrCheckpointMismatch, number, hash.Hex(), expected.Hex())
+	}
+	return nil
+}
+
+// verifyCheckpoints ensures that the chain of the BNB node agrees with every checkpoint it has reached.
+// Checkpoints above the BNB tip are checked later, when the corresponding headers get relayed.
+func (r *BNBReporter) verifyCheckpoints() error {
+	if len(r.checkpoints) == 0 {
+		return nil
+	}
+
+	bnbTipNumber, err := r.client.BlockNumber(r.ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, number := range r.checkpoints.numbers() {
+		if number > bnbTipNumber {
+			break
+		}
+		header, err := r.client.HeaderByNumber(r.ctx, number)
+		if err != nil {
+			return fmt.Errorf("failed to get BNB header at checkpoint %d: %w: %w", number, types.ErrSourceChainUnavailable, err)
+		}
+		if err := r.checkpoints.check(header); err != nil {
+			return err
+		}
+	}
+
+	r.logger.Debugf("BNB node agrees with %d checkpoints", len(r.checkpoints))
+	return nil
+}
+
+// checkHeadersAgainstCheckpoints ensures none of the given headers contradicts a checkpoint
+func (r *BNBReporter) checkHeadersAgainstCheckpoints(headers []*bnbtypes.Header) error {
+	for _, header := range headers {
+		if err := r.checkpoints.check(header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
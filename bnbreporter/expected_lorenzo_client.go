@@ -7,8 +7,14 @@ import (
 	pv "github.com/cosmos/relayer/v2/relayer/provider"
 )
 
+// LorenzoClient queries Lorenzo and sends its txs. Queries give up once the given context is done or the
+// timeout of the Lorenzo config passes.
 type LorenzoClient interface {
 	MustGetAddr() string
-	BNBUploadHeaders(ctx context.Context, msgHeaders *types.MsgUploadHeaders) (*pv.RelayerTxResponse, error)
-	BNBLatestHeader() (*types.Header, error)
+	// Paused returns whether broadcasting is paused because the signers ran out of funds
+	Paused() bool
+	// BNBUploadHeaders returns the address of the key that signed the tx, which replaces msgHeaders.Signer
+	BNBUploadHeaders(ctx context.Context, msgHeaders *types.MsgUploadHeaders) (*pv.RelayerTxResponse, string, error)
+	TxGasUsed(ctx context.Context, txHash string) (int64, error)
+	BNBLatestHeader(ctx context.Context) (*types.Header, error)
 }
@@ -0,0 +1,90 @@
+package bnbreporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Lorenzo-Protocol/lorenzo/v3/x/bnblightclient/types"
+	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbclient/bnbtypes"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/tracing"
+	relayertypes "github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// queryLorenzoTip queries the latest BNB header on Lorenzo and journals the response
+func (r *BNBReporter) queryLorenzoTip() (*types.Header, error) {
+	header, err := r.lorenzoClient.BNBLatestHeader(r.ctx)
+	if r.journal != nil {
+		tip := &journal.Tip{}
+		if err != nil {
+			tip.Error = err.Error()
+		} else {
+			tip.Height = header.Number
+			tip.Hash = common.BytesToHash(header.Hash).Hex()
+		}
+		r.journal.Record(journal.ModuleBNBReporter, journal.KindTip, tip)
+	}
+	return header, err
+}
+
+// uploadHeaders uploads the given BNB headers to Lorenzo and journals the outcome
+func (r *BNBReporter) uploadHeaders(ctx context.Context, headers []*bnbtypes.Header) error {
+	if r.lorenzoClient.Paused() {
+		return relayertypes.ErrSignersPaused
+	}
+
+	heights := trace.WithAttributes(
+		tracing.FirstHeightKey.Int64(headers[0].Number.Int64()),
+		tracing.LastHeightKey.Int64(headers[len(headers)-1].Number.Int64()),
+	)
+	_, span := tracer.Start(ctx, "bnbreporter.BuildMessage", heights)
+	lorenzoBNBHeaders, err := ConvertBNBHeaderToLorenzoBNBHeaders(headers)
+	tracing.End(span, err)
+	if err != nil {
+		return err
+	}
+
+	ctx, span = tracer.Start(ctx, "bnbreporter.Broadcast", heights)
+	msg := &types.MsgUploadHeaders{
+		Signer:  r.lorenzoClient.MustGetAddr(),
+		Headers: lorenzoBNBHeaders,
+	}
+	res, signer, err := r.lorenzoClient.BNBUploadHeaders(ctx, msg)
+	if res != nil {
+		span.SetAttributes(tracing.TxHashKey.String(res.TxHash), tracing.TxCodeKey.Int64(int64(res.Code)))
+	}
+	tracing.End(span, err)
+	r.auditUploadHeaders(ctx, headers, signer, res, err)
+	if err != nil && res == nil && !errors.Is(err, relayertypes.ErrInsufficientFunds) {
+		// no tx response, the broadcast did not reach Lorenzo
+		err = fmt.Errorf("failed to upload BNB headers: %w: %w", relayertypes.ErrLorenzoUnavailable, err)
+	}
+	if err == nil {
+		r.metrics.UploadedHeadersCounter.Add(float64(len(headers)))
+		r.metrics.BatchSizeHistogram.Observe(float64(len(headers)))
+		r.alerts.Relayed(journal.ModuleBNBReporter)
+	} else {
+		r.metrics.FailedUploadsCounter.Inc()
+	}
+
+	if r.journal != nil {
+		submission := &journal.Submission{
+			FirstHeight: headers[0].Number.Uint64(),
+			LastHeight:  headers[len(headers)-1].Number.Uint64(),
+			Submitted:   make([]string, 0, len(headers)),
+		}
+		if err == nil {
+			for _, header := range headers {
+				submission.Submitted = append(submission.Submitted, header.Hash().Hex())
+			}
+		} else {
+			submission.Error = err.Error()
+		}
+		r.journal.Record(journal.ModuleBNBReporter, journal.KindSubmission, submission)
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package bnbreporter
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbclient"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbclient/bnbtypes"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+)
+
+// meteredClient records the latency and errors of the calls to the BNB node
+type meteredClient struct {
+	bnbclient.BNBClient
+	metrics *metrics.BNBReporterMetrics
+}
+
+var _ bnbclient.BNBClient = (*meteredClient)(nil)
+
+// observe records a call of the given method that started at start
+func (c *meteredClient) observe(method string, start time.Time, err error) {
+	c.metrics.RPCDurationHistogramVec.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		c.metrics.RPCErrorsCounterVec.WithLabelValues(method).Inc()
+	}
+}
+
+func (c *meteredClient) BlockNumber(ctx context.Context) (number uint64, err error) {
+	defer func(start time.Time) { c.observe("BlockNumber", start, err) }(time.Now())
+	return c.BNBClient.BlockNumber(ctx)
+}
+
+func (c *meteredClient) LatestHeader(ctx context.Context) (header *bnbtypes.Header, err error) {
+	defer func(start time.Time) { c.observe("LatestHeader", start, err) }(time.Now())
+	return c.BNBClient.LatestHeader(ctx)
+}
+
+func (c *meteredClient) RangeHeaders(ctx context.Context, start, end uint64) (headers []*bnbtypes.Header, err error) {
+	defer func(t time.Time) { c.observe("RangeHeaders", t, err) }(time.Now())
+	return c.BNBClient.RangeHeaders(ctx, start, end)
+}
+
+func (c *meteredClient) HeaderByNumber(ctx context.Context, number uint64) (header *bnbtypes.Header, err error) {
+	defer func(start time.Time) { c.observe("HeaderByNumber", start, err) }(time.Now())
+	return c.BNBClient.HeaderByNumber(ctx, number)
+}
+
+func (c *meteredClient) HeaderByHash(ctx context.Context, hash common.Hash) (header *bnbtypes.Header, err error) {
+	defer func(start time.Time) { c.observe("HeaderByHash", start, err) }(time.Now())
+	return c.BNBClient.HeaderByHash(ctx, hash)
+}
+
+// newClient connects to the BNB node of the config
+func (r *BNBReporter) newClient() (bnbclient.BNBClient, error) {
+	client, err := bnbclient.New(r.cfg.RpcUrl, r.cfg.RPCTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return &meteredClient{BNBClient: client, metrics: r.metrics}, nil
+}
+
+// recordTips updates the tip and lag metrics with the given BNB tip and the tip of Lorenzo's BNB light client
+func (r *BNBReporter) recordTips(bnbTip *bnbtypes.Header) {
+	r.metrics.BNBTipGauge.Set(float64(bnbTip.Number.Uint64()))
+	r.metrics.LorenzoTipGauge.Set(float64(r.lorenzoTip.Number.Uint64()))
+	r.metrics.LagBlocksGauge.Set(float64(bnbTip.Number.Int64() - r.lorenzoTip.Number.Int64()))
+	r.metrics.LagSecondsGauge.Set(float64(int64(bnbTip.Time) - int64(r.lorenzoTip.Time)))
+}
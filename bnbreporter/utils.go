@@ -2,6 +2,7 @@ package bnbreporter
 
 import (
 	"bytes"
+	"time"
 
 	"github.com/Lorenzo-Protocol/lorenzo/v3/x/bnblightclient/types"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -38,3 +39,26 @@ func ConvertBNBHeaderToLorenzoBNBHeaders(headers []*bnbtypes.Header) ([]*types.H
 
 	return lorenzoBNBHeaders, nil
 }
+
+// waitUntilUnpaused blocks while the Lorenzo signers are paused for low funds.
+// It returns false if the reporter is asked to stop meanwhile.
+func (r *BNBReporter) waitUntilUnpaused() bool {
+	if !r.lorenzoClient.Paused() {
+		return true
+	}
+	r.logger.Warnf("Lorenzo signers are paused for low funds, waiting until they are funded")
+
+	ticker := time.NewTicker(PausedCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !r.lorenzoClient.Paused() {
+				r.logger.Infof("Lorenzo signers resumed")
+				return true
+			}
+		case <-r.quit:
+			return false
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package bnbreporter
+
+import (
+	"time"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+)
+
+// watchdogState tracks relay progress for the watchdog. It is only used by the main loop.
+type watchdogState struct {
+	sourceTip   uint64    // BNB tip at the last successful poll
+	relayed     uint64    // height of the last relayed header at the last check
+	lastRelayed time.Time // when the relayed height last advanced
+	lorenzoDown bool      // whether the last attempt to relay failed because Lorenzo was unavailable
+}
+
+// checkStall reconnects to the BNB node and bootstraps again if the reporter relayed nothing for the
+// configured stall threshold. BNB produces a block every few seconds, so a relay that does not advance means
+// the node is stuck or unreachable, or the headers keep failing to upload. Lorenzo outages and paused signers
+// do not count as a stall, reconnecting to the BNB node would not help.
+func (r *BNBReporter) checkStall(paused bool) {
+	w := &r.watchdog
+	stallAfter := time.Duration(r.stallAfter.Load())
+	relayed := r.lorenzoTip.Number.Uint64()
+	now := time.Now()
+	if stallAfter == 0 || paused || w.lorenzoDown || relayed != w.relayed || w.lastRelayed.IsZero() {
+		w.relayed, w.lastRelayed = relayed, now
+		return
+	}
+	if stalledFor := now.Sub(w.lastRelayed); stalledFor >= stallAfter {
+		r.intervene(relayed, stalledFor)
+		// give the intervention a full period to take effect
+		w.relayed, w.lastRelayed = r.lorenzoTip.Number.Uint64(), time.Now()
+	}
+}
+
+// intervene reconnects to the BNB node and bootstraps from Lorenzo's tip again, recording the intervention
+func (r *BNBReporter) intervene(relayed uint64, stalledFor time.Duration) {
+	r.logger.Warnf("Relayed nothing for %v, last relayed header %d, last BNB tip %d. Reconnecting to the BNB node and bootstrapping again",
+		stalledFor.Round(time.Second), relayed, r.watchdog.sourceTip)
+	r.metrics.WatchdogInterventionsCounter.Inc()
+
+	intervention := &journal.Intervention{
+		Action:     "reconnect-bootstrap",
+		SourceTip:  r.watchdog.sourceTip,
+		RelayedTip: relayed,
+		StalledFor: stalledFor.Round(time.Second).String(),
+	}
+	defer r.journal.Record(journal.ModuleBNBReporter, journal.KindIntervention, intervention)
+
+	client, err := r.newClient()
+	if err != nil {
+		r.logger.Errorf("Failed to reconnect to the BNB node: %v", err)
+		intervention.Error = err.Error()
+		return
+	}
+	r.client.Close()
+	r.client = client
+
+	if err := r.boostrap(); err != nil {
+		r.logger.Errorf("Failed to bootstrap: %v", err)
+		intervention.Error = err.Error()
+		r.alerts.BootstrapFailed(journal.ModuleBNBReporter, err)
+		return
+	}
+	r.alerts.Bootstrapped(journal.ModuleBNBReporter)
+}
@@ -0,0 +1,139 @@
+package bnbreporter
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Lorenzo-Protocol/lorenzo/v3/x/bnblightclient/types"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbclient/bnbtypes"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+)
+
+// fakeLorenzoClient is a BNB light client whose tip is the last uploaded header
+type fakeLorenzoClient struct {
+	tip *types.Header
+}
+
+func (c *fakeLorenzoClient) MustGetAddr() string { return "signer" }
+func (c *fakeLorenzoClient) Paused() bool        { return false }
+
+func (c *fakeLorenzoClient) BNBUploadHeaders(_ context.Context, msg *types.MsgUploadHeaders) (*pv.RelayerTxResponse, string, error) {
+	c.tip = msg.Headers[len(msg.Headers)-1]
+	return &pv.RelayerTxResponse{}, "signer", nil
+}
+
+func (c *fakeLorenzoClient) TxGasUsed(context.Context, string) (int64, error) {
+	return 0, nil
+}
+
+func (c *fakeLorenzoClient) BNBLatestHeader(context.Context) (*types.Header, error) {
+	return c.tip, nil
+}
+
+func testHeader(number int64) *bnbtypes.Header {
+	return &bnbtypes.Header{Number: big.NewInt(number), Difficulty: big.NewInt(2)}
+}
+
+func TestWatchdogInterventions(t *testing.T) {
+	lorenzoTip, err := ConvertBNBHeaderToLorenzoBNBHeaders([]*bnbtypes.Header{testHeader(100)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lorenzoClient := &fakeLorenzoClient{tip: lorenzoTip[0]}
+	journalPath := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := journal.New(&config.JournalConfig{Enabled: true, Path: journalPath}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the client is only dialed on the first call
+	cfg := &config.BNBReporterConfig{RpcUrl: "http://127.0.0.1:1", StallAfter: time.Minute}
+	r, err := New(zap.NewNop(), lorenzoClient, cfg, metrics.NewBNBReporterMetrics(prometheus.NewRegistry()), j, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.lorenzoTip = testHeader(99)
+	r.watchdog.sourceTip = 120
+	interventions := func() float64 { return testutil.ToFloat64(r.metrics.WatchdogInterventionsCounter) }
+	stalledSince := func() { r.watchdog.lastRelayed = time.Now().Add(-2 * cfg.StallAfter) }
+
+	// the first check starts counting
+	r.checkStall(false)
+	if r.watchdog.lastRelayed.IsZero() || interventions() != 0 {
+		t.Fatal("expected the watchdog to start counting without intervening")
+	}
+
+	// progress resets the stall
+	stalledSince()
+	r.lorenzoTip = testHeader(100)
+	r.checkStall(false)
+	if time.Since(r.watchdog.lastRelayed) >= cfg.StallAfter || interventions() != 0 {
+		t.Fatal("expected relay progress to reset the stall")
+	}
+
+	// paused signers and Lorenzo outages are no stalls
+	stalledSince()
+	r.checkStall(true)
+	if time.Since(r.watchdog.lastRelayed) >= cfg.StallAfter || interventions() != 0 {
+		t.Fatal("expected the watchdog to skip the check while paused")
+	}
+	stalledSince()
+	r.watchdog.lorenzoDown = true
+	r.checkStall(false)
+	r.watchdog.lorenzoDown = false
+	if time.Since(r.watchdog.lastRelayed) >= cfg.StallAfter || interventions() != 0 {
+		t.Fatal("expected the watchdog to skip the check while Lorenzo is unavailable")
+	}
+
+	// the threshold is applied on reload
+	r.UpdateConfig(&config.BNBReporterConfig{StallAfter: time.Hour})
+	stalledSince()
+	r.checkStall(false)
+	if interventions() != 0 {
+		t.Fatal("expected the reloaded threshold to hold the intervention back")
+	}
+
+	// a stall beyond the threshold reconnects and bootstraps from Lorenzo's tip
+	r.watchdog.lastRelayed = time.Now().Add(-2 * time.Hour)
+	r.lorenzoTip = testHeader(90)
+	r.watchdog.relayed = 90
+	r.checkStall(false)
+	if interventions() != 1 {
+		t.Fatalf("expected one intervention, got %v", interventions())
+	}
+	if r.lorenzoTip.Number.Uint64() != 100 {
+		t.Fatalf("expected the bootstrap to restore Lorenzo's tip 100, got %d", r.lorenzoTip.Number.Uint64())
+	}
+
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+	records, err := journal.ReadFile(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intervention *journal.Intervention
+	for _, record := range records {
+		if record.Kind == journal.KindIntervention {
+			intervention = &journal.Intervention{}
+			if err := record.Decode(intervention); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if intervention == nil {
+		t.Fatal("expected the intervention to be journaled")
+	}
+	if intervention.Action != "reconnect-bootstrap" || intervention.SourceTip != 120 || intervention.RelayedTip != 90 || intervention.Error != "" {
+		t.Fatalf("unexpected intervention record: %+v", intervention)
+	}
+}
@@ -6,6 +6,7 @@
 package btcclient
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -39,12 +40,12 @@ type Client struct {
 	blockEventChan chan *types.BlockEvent
 }
 
-func (c *Client) GetTipBlockVerbose() (*btcjson.GetBlockVerboseResult, error) {
-	tipBtcHash, err := c.GetBestBlockHash()
+func (c *Client) GetTipBlockVerbose(ctx context.Context) (*btcjson.GetBlockVerboseResult, error) {
+	tipBtcHash, err := c.GetBestBlockHash(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to obtain BTC block tip: %w", err)
 	}
-	tipBlock, err := c.GetBlockVerbose(tipBtcHash)
+	tipBlock, err := c.GetBlockVerbose(ctx, tipBtcHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to obtain BTC tip block: %w", err)
 	}
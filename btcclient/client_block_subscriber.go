@@ -11,7 +11,6 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
-	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/netparams"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/zmq"
 )
@@ -20,7 +19,7 @@ import (
 // used by vigilant reporter
 func NewWithBlockSubscriber(cfg *config.BTCConfig, retrySleepTime, maxRetrySleepTime time.Duration, parentLogger *zap.Logger) (*Client, error) {
 	client := &Client{}
-	params, err := netparams.GetBTCParams(cfg.NetParams)
+	params, err := cfg.Networks.GetBTCParams(cfg.NetParams)
 	if err != nil {
 		return nil, err
 	}
@@ -150,6 +149,19 @@ func (c *Client) MustSubscribeBlocks() {
 	}
 }
 
+// ResubscribeBlocks reconnects the block subscription, for when it silently stopped delivering block events
+func (c *Client) ResubscribeBlocks() error {
+	switch c.Cfg.BtcBackend {
+	case types.Btcd:
+		// the websocket reconnects on its own, registering the notifications again
+		c.Disconnect()
+		return c.subscribeBlocksByWebSocket()
+	case types.Bitcoind:
+		return c.zmqClient.Resubscribe()
+	}
+	return nil
+}
+
 func (c *Client) BlockEventChan() <-chan *types.BlockEvent {
 	return c.blockEventChan
 }
@@ -1,6 +1,8 @@
 package btcclient
 
 import (
+	"context"
+
 	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -11,20 +13,25 @@ import (
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
+// BTCClient queries the BTC node. Every call gives up once the given context is done or the per-call
+// deadline of the config passes.
 type BTCClient interface {
 	Stop()
 	WaitForShutdown()
 	MustSubscribeBlocks()
+	ResubscribeBlocks() error
 	BlockEventChan() <-chan *types.BlockEvent
-	GetBestBlock() (*chainhash.Hash, uint64, error)
-	GetBlockByHash(blockHash *chainhash.Hash) (*types.IndexedBlock, *wire.MsgBlock, error)
-	FindTailBlocksByHeight(height uint64) ([]*types.IndexedBlock, error)
-	FindRangeBlocksByHeight(startHeight, endHeight uint64) ([]*types.IndexedBlock, error)
-	GetBlockByHeight(height uint64) (*types.IndexedBlock, *wire.MsgBlock, error)
-	GetTxOut(txHash *chainhash.Hash, index uint32, mempool bool) (*btcjson.GetTxOutResult, error)
-	SendRawTransaction(tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error)
-	GetTransaction(txHash *chainhash.Hash) (*btcjson.GetTransactionResult, error)
-	GetRawTransaction(txHash *chainhash.Hash) (*btcutil.Tx, error)
+	GetBestBlock(ctx context.Context) (*chainhash.Hash, uint64, error)
+	GetBlockChainInfo(ctx context.Context) (*btcjson.GetBlockChainInfoResult, error)
+	GetBlockHash(ctx context.Context, blockHeight int64) (*chainhash.Hash, error)
+	GetBlockByHash(ctx context.Context, blockHash *chainhash.Hash) (*types.IndexedBlock, *wire.MsgBlock, error)
+	FindTailBlocksByHeight(ctx context.Context, height uint64) ([]*types.IndexedBlock, error)
+	FindRangeBlocksByHeight(ctx context.Context, startHeight, endHeight uint64) ([]*types.IndexedBlock, error)
+	GetBlockByHeight(ctx context.Context, height uint64) (*types.IndexedBlock, *wire.MsgBlock, error)
+	GetTxOut(ctx context.Context, txHash *chainhash.Hash, index uint32, mempool bool) (*btcjson.GetTxOutResult, error)
+	SendRawTransaction(ctx context.Context, tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error)
+	GetTransaction(ctx context.Context, txHash *chainhash.Hash) (*btcjson.GetTransactionResult, error)
+	GetRawTransaction(ctx context.Context, txHash *chainhash.Hash) (*btcutil.Tx, error)
 }
 
 type BTCWallet interface {
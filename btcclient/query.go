@@ -1,6 +1,7 @@
 package btcclient
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -11,12 +12,12 @@ import (
 
 // GetBestBlock provides similar functionality with the btcd.rpcclient.GetBestBlock function
 // We implement this, because this function is only provided by btcd.
-func (c *Client) GetBestBlock() (*chainhash.Hash, uint64, error) {
-	btcLatestBlockHash, err := c.GetBestBlockHash()
+func (c *Client) GetBestBlock(ctx context.Context) (*chainhash.Hash, uint64, error) {
+	btcLatestBlockHash, err := c.GetBestBlockHash(ctx)
 	if err != nil {
 		return nil, 0, err
 	}
-	btcLatestBlock, err := c.GetBlockVerbose(btcLatestBlockHash)
+	btcLatestBlock, err := c.GetBlockVerbose(ctx, btcLatestBlockHash)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -24,13 +25,13 @@ func (c *Client) GetBestBlock() (*chainhash.Hash, uint64, error) {
 	return btcLatestBlockHash, btcLatestBlockHeight, nil
 }
 
-func (c *Client) GetBlockByHash(blockHash *chainhash.Hash) (*types.IndexedBlock, *wire.MsgBlock, error) {
-	blockInfo, err := c.GetBlockVerbose(blockHash)
+func (c *Client) GetBlockByHash(ctx context.Context, blockHash *chainhash.Hash) (*types.IndexedBlock, *wire.MsgBlock, error) {
+	blockInfo, err := c.GetBlockVerbose(ctx, blockHash)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get block verbose by hash %s: %w", blockHash.String(), err)
 	}
 
-	mBlock, err := c.GetBlock(blockHash)
+	mBlock, err := c.GetBlock(ctx, blockHash)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get block by hash %s: %w", blockHash.String(), err)
 	}
@@ -40,18 +41,18 @@ func (c *Client) GetBlockByHash(blockHash *chainhash.Hash) (*types.IndexedBlock,
 }
 
 // GetBlockByHeight returns a block with the given height
-func (c *Client) GetBlockByHeight(height uint64) (*types.IndexedBlock, *wire.MsgBlock, error) {
-	blockHash, err := c.GetBlockHash(int64(height))
+func (c *Client) GetBlockByHeight(ctx context.Context, height uint64) (*types.IndexedBlock, *wire.MsgBlock, error) {
+	blockHash, err := c.GetBlockHash(ctx, int64(height))
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get block by height %d: %w", height, err)
 	}
 
-	return c.GetBlockByHash(blockHash)
+	return c.GetBlockByHash(ctx, blockHash)
 }
 
 // getChainBlocks returns a chain of indexed blocks from the block at baseHeight to the tipBlock
 // note: the caller needs to ensure that tipBlock is on the blockchain
-func (c *Client) getChainBlocks(baseHeight uint64, tipBlock *types.IndexedBlock) ([]*types.IndexedBlock, error) {
+func (c *Client) getChainBlocks(ctx context.Context, baseHeight uint64, tipBlock *types.IndexedBlock) ([]*types.IndexedBlock, error) {
 	tipHeight := uint64(tipBlock.Height)
 	if tipHeight < baseHeight {
 		return nil, fmt.Errorf("the tip block height %v is less than the base height %v", tipHeight, baseHeight)
@@ -69,7 +70,7 @@ func (c *Client) getChainBlocks(baseHeight uint64, tipBlock *types.IndexedBlock)
 	// minus 2 is because the tip block is already put in the last position of the slice,
 	// and it is ensured that the length of chainBlocks is more than 1
 	for i := len(chainBlocks) - 2; i >= 0; i-- {
-		ib, mb, err := c.GetBlockByHash(prevHash)
+		ib, mb, err := c.GetBlockByHash(ctx, prevHash)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get block by hash %x: %w", prevHash, err)
 		}
@@ -80,12 +81,12 @@ func (c *Client) getChainBlocks(baseHeight uint64, tipBlock *types.IndexedBlock)
 	return chainBlocks, nil
 }
 
-func (c *Client) getBestIndexedBlock() (*types.IndexedBlock, error) {
-	tipHash, err := c.GetBestBlockHash()
+func (c *Client) getBestIndexedBlock(ctx context.Context) (*types.IndexedBlock, error) {
+	tipHash, err := c.GetBestBlockHash(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get the best block: %w", err)
 	}
-	tipIb, _, err := c.GetBlockByHash(tipHash)
+	tipIb, _, err := c.GetBlockByHash(ctx, tipHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get the block by hash %x: %w", tipHash, err)
 	}
@@ -94,8 +95,8 @@ func (c *Client) getBestIndexedBlock() (*types.IndexedBlock, error) {
 }
 
 // FindTailBlocksByHeight returns the chain of blocks from the block at baseHeight to the tip
-func (c *Client) FindTailBlocksByHeight(baseHeight uint64) ([]*types.IndexedBlock, error) {
-	tipIb, err := c.getBestIndexedBlock()
+func (c *Client) FindTailBlocksByHeight(ctx context.Context, baseHeight uint64) ([]*types.IndexedBlock, error) {
+	tipIb, err := c.getBestIndexedBlock(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -104,14 +105,14 @@ func (c *Client) FindTailBlocksByHeight(baseHeight uint64) ([]*types.IndexedBloc
 		return nil, fmt.Errorf("invalid base height %d, should not be higher than tip block %d", baseHeight, tipIb.Height)
 	}
 
-	return c.getChainBlocks(baseHeight, tipIb)
+	return c.getChainBlocks(ctx, baseHeight, tipIb)
 }
 
-func (c *Client) FindRangeBlocksByHeight(startHeight, endHeight uint64) ([]*types.IndexedBlock, error) {
-	endId, _, err := c.GetBlockByHeight(endHeight)
+func (c *Client) FindRangeBlocksByHeight(ctx context.Context, startHeight, endHeight uint64) ([]*types.IndexedBlock, error) {
+	endId, _, err := c.GetBlockByHeight(ctx, endHeight)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.getChainBlocks(startHeight, endId)
+	return c.getChainBlocks(ctx, startHeight, endId)
 }
@@ -0,0 +1,78 @@
+package btcclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// The RPCs of rpcclient take no context. The wrappers below send them asynchronously and stop waiting for the
+// response once the caller's context is done or the per-call deadline (btc.rpc-timeout) passes.
+
+// receive waits for the response of an RPC sent asynchronously, at most for the given timeout if positive
+func receive[T any](ctx context.Context, timeout time.Duration, wait func() (T, error)) (T, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type response struct {
+		result T
+		err    error
+	}
+	// buffered, so that the receiving goroutine ends once the response arrives or the client shuts down
+	responses := make(chan response, 1)
+	go func() {
+		result, err := wait()
+		responses <- response{result, err}
+	}()
+
+	select {
+	case res := <-responses:
+		return res.result, res.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func (c *Client) GetBestBlockHash(ctx context.Context) (*chainhash.Hash, error) {
+	return receive(ctx, c.Cfg.RPCTimeout, c.Client.GetBestBlockHashAsync().Receive)
+}
+
+func (c *Client) GetBlockHash(ctx context.Context, blockHeight int64) (*chainhash.Hash, error) {
+	return receive(ctx, c.Cfg.RPCTimeout, c.Client.GetBlockHashAsync(blockHeight).Receive)
+}
+
+func (c *Client) GetBlock(ctx context.Context, blockHash *chainhash.Hash) (*wire.MsgBlock, error) {
+	return receive(ctx, c.Cfg.RPCTimeout, c.Client.GetBlockAsync(blockHash).Receive)
+}
+
+func (c *Client) GetBlockVerbose(ctx context.Context, blockHash *chainhash.Hash) (*btcjson.GetBlockVerboseResult, error) {
+	return receive(ctx, c.Cfg.RPCTimeout, c.Client.GetBlockVerboseAsync(blockHash).Receive)
+}
+
+func (c *Client) GetBlockChainInfo(ctx context.Context) (*btcjson.GetBlockChainInfoResult, error) {
+	return receive(ctx, c.Cfg.RPCTimeout, c.Client.GetBlockChainInfoAsync().Receive)
+}
+
+func (c *Client) GetTxOut(ctx context.Context, txHash *chainhash.Hash, index uint32, mempool bool) (*btcjson.GetTxOutResult, error) {
+	return receive(ctx, c.Cfg.RPCTimeout, c.Client.GetTxOutAsync(txHash, index, mempool).Receive)
+}
+
+func (c *Client) SendRawTransaction(ctx context.Context, tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error) {
+	return receive(ctx, c.Cfg.RPCTimeout, c.Client.SendRawTransactionAsync(tx, allowHighFees).Receive)
+}
+
+func (c *Client) GetTransaction(ctx context.Context, txHash *chainhash.Hash) (*btcjson.GetTransactionResult, error) {
+	return receive(ctx, c.Cfg.RPCTimeout, c.Client.GetTransactionAsync(txHash).Receive)
+}
+
+func (c *Client) GetRawTransaction(ctx context.Context, txHash *chainhash.Hash) (*btcutil.Tx, error) {
+	return receive(ctx, c.Cfg.RPCTimeout, c.Client.GetRawTransactionAsync(txHash).Receive)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/audit"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
+// GetAuditCmd returns the CLI commands that inspect the audit log of the header txs
+func GetAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the audit log of the header txs sent to Lorenzo",
+	}
+	cmd.AddCommand(getAuditVerifyCmd())
+	return cmd
+}
+
+func getAuditVerifyCmd() *cobra.Command {
+	var auditPath string
+	var cfgFile string
+	var cfgOverlays []string
+
+	cmd := &cobra.Command{
+		Use:          "verify",
+		Short:        "Check that no entry of the audit log was edited or deleted",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if len(auditPath) == 0 {
+				cfg, err := config.New(cfgFile, cfgOverlays...)
+				if err != nil {
+					return err
+				}
+				auditPath = cfg.Audit.Path
+			}
+
+			entries, err := audit.Verify(auditPath)
+			if err != nil {
+				return fmt.Errorf("audit log %s is broken after %d valid entries: %w", auditPath, entries, err)
+			}
+			cmd.Printf("Audit log %s is intact, %d entries\n", auditPath, entries)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&auditPath, "file", "", "path of the audit log (defaults to audit.path in the config)")
+	cmd.Flags().StringVar(&cfgFile, "config", config.DefaultConfigFile(), "config file")
+	cmd.Flags().StringSliceVar(&cfgOverlays, "config-overlay", nil, "config files merged over the config file, in order")
+	return cmd
+}
@@ -1,59 +1,29 @@
 package cmd
 
 import (
-	"fmt"
-
-	lrzclient "github.com/Lorenzo-Protocol/lorenzo-sdk/v3/client"
 	"github.com/spf13/cobra"
 
-	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbreporter"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
 )
 
 func GetBNBReporterCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "bnbreporter",
-		Short: "Lrzrelayer BNB reporter",
-		Run:   bnbReporterAction,
+		Use:          "bnbreporter",
+		Short:        "Lrzrelayer BNB reporter",
+		Long:         "Runs the BNB reporter regardless of bnbreporter.enabled, restarting it with backoff like start does.",
+		SilenceUsage: true,
+		RunE:         bnbReporterAction,
 	}
+	cmd.Flags().String("lorenzo-key-dir", "", "Directory of the Lorenzo key")
 	cmd.Flags().String("config", config.DefaultConfigFile(), "config file")
+	cmd.Flags().StringSlice("config-overlay", nil, "config files merged over the config file, in order")
 
 	return cmd
 }
 
-func bnbReporterAction(cmd *cobra.Command, args []string) {
+func bnbReporterAction(cmd *cobra.Command, args []string) error {
 	cfgFile, _ := cmd.Flags().GetString("config")
-	cfg, err := config.New(cfgFile)
-	if err != nil {
-		panic(err)
-	}
-	if err := cfg.BNBReporter.Validate(); err != nil {
-		panic(fmt.Errorf("invalid config in bnbreporter: %w", err))
-	}
-
-	rootLogger, err := cfg.CreateLogger()
-	if err != nil {
-		panic(fmt.Errorf("failed to create logger: %w", err))
-	}
-
-	// create Lorenzo client. Note that requests from Lorenzo client are ad hoc
-	lorenzoClient, err := lrzclient.New(&cfg.Lorenzo, nil)
-	if err != nil {
-		panic(fmt.Errorf("failed to open Lorenzo client: %w", err))
-	}
-
-	bnbReporter, err := bnbreporter.New(rootLogger, lorenzoClient, &cfg.BNBReporter)
-	if err != nil {
-		panic(fmt.Errorf("failed to create BNB reporter: %w", err))
-	}
-	bnbReporter.Start()
-
-	addInterruptHandler(func() {
-		rootLogger.Info("Stopping BNB reporter...")
-		bnbReporter.Stop()
-		bnbReporter.WaitForShutdown()
-		rootLogger.Info("Reporter BNB shutdown")
-	})
-	<-interruptHandlersDone
-	rootLogger.Info("Shutdown complete")
+	cfgOverlays, _ := cmd.Flags().GetStringSlice("config-overlay")
+	lorenzoKeyDir, _ := cmd.Flags().GetString("lorenzo-key-dir")
+	return runReporters("bnbreporter-cmd", cfgFile, cfgOverlays, lorenzoKeyDir, []string{config.SignerBNBReporter})
 }
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// GetConfigCmd returns the CLI commands that create, check and print config files
+func GetConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Create, validate and show the lrzrelayer config",
+	}
+	cmd.AddCommand(
+		getConfigInitCmd(),
+		getConfigValidateCmd(),
+		getConfigShowCmd(),
+	)
+	return cmd
+}
+
+func getConfigInitCmd() *cobra.Command {
+	var network string
+	var output string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:          "init",
+		Short:        "Write a commented default config for the given Bitcoin network",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.DefaultConfig(network)
+			if err != nil {
+				return err
+			}
+
+			if _, err := os.Stat(output); err == nil && !force {
+				return fmt.Errorf("config file %s already exists, use --force to overwrite it", output)
+			} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(output), 0o700); err != nil {
+				return err
+			}
+			// the config holds the BTC node password
+			f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+			if err != nil {
+				return err
+			}
+			if err := cfg.WriteYAML(f, false); err != nil {
+				_ = f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+
+			cmd.Printf("Wrote the default %s config to %s, set lorenzo.chain-id and bnbreporter.base_height before running the reporters\n",
+				network, output)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&network, "network", types.BtcMainnet.String(), "Bitcoin network (mainnet|testnet|testnet4|simnet|regtest|signet)")
+	cmd.Flags().StringVar(&output, "output", config.DefaultConfigFile(), "path of the config file to write")
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite an existing config file")
+	return cmd
+}
+
+func getConfigValidateCmd() *cobra.Command {
+	var cfgFile string
+	var cfgOverlays []string
+	var reporters []string
+
+	cmd := &cobra.Command{
+		Use:          "validate",
+		Short:        "Validate the config of the given reporters",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.New(cfgFile, cfgOverlays...)
+			if err != nil {
+				return err
+			}
+			if err := cfg.ValidateReporters(reporters...); err != nil {
+				return err
+			}
+			cmd.Printf("Config is valid for %v\n", reporters)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&cfgFile, "config", config.DefaultConfigFile(), "config file")
+	cmd.Flags().StringSliceVar(&cfgOverlays, "config-overlay", nil, "config files merged over the config file, in order")
+	cmd.Flags().StringSliceVar(&reporters, "reporters", []string{config.SignerReporter, config.SignerBNBReporter},
+		"reporters whose config is validated (reporter|bnbreporter)")
+	return cmd
+}
+
+func getConfigShowCmd() *cobra.Command {
+	var cfgFile string
+	var cfgOverlays []string
+
+	cmd := &cobra.Command{
+		Use:          "show",
+		Short:        "Print the effective config, merged from the config files and the environment, with secrets redacted",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.New(cfgFile, cfgOverlays...)
+			if err != nil {
+				return err
+			}
+			return cfg.WriteYAML(cmd.OutOrStdout(), true)
+		},
+	}
+	cmd.Flags().StringVar(&cfgFile, "config", config.DefaultConfigFile(), "config file")
+	cmd.Flags().StringSliceVar(&cfgOverlays, "config-overlay", nil, "config files merged over the config file, in order")
+	return cmd
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// startMetricsServer starts the Prometheus metrics server, which also serves the admin endpoint of rl, and stops it
+// on shutdown. A failure of the server after it started is handed to onFailure.
+func startMetricsServer(cfg *config.MetricsConfig, reg *prometheus.Registry, rl *reloader, logger *zap.Logger, onFailure func(error)) error {
+	server := metrics.NewServer(cfg, reg, logger)
+	rl.addApplier(func(next *config.Config) error {
+		return next.Metrics.Validate()
+	}, func(next *config.Config) {
+		server.UpdateConfig(&next.Metrics)
+	})
+	rl.start(server)
+	if err := server.Start(); err != nil {
+		return fmt.Errorf("failed to start metrics server: %w: %w", types.ErrConfig, err)
+	}
+	go func() {
+		if err, failed := <-server.Err(); failed {
+			onFailure(fmt.Errorf("metrics server failed: %w", err))
+		}
+	}()
+
+	// registered early so that it runs late, keeping the metrics available while the reporters stop
+	addInterruptHandler(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+		defer cancel()
+		if err := server.Stop(ctx); err != nil {
+			logger.Sugar().Errorf("Failed to stop metrics server: %v", err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+)
+
+// reloadPath is the admin endpoint, served next to the metrics, that reloads the config like SIGHUP does
+const reloadPath = "/admin/reload"
+
+// reloadApplier validates and applies the reloadable part of a new config to a running component. Applying
+// cannot fail, so that a config every applier validated is applied as a whole.
+type reloadApplier struct {
+	validate func(cfg *config.Config) error
+	apply    func(cfg *config.Config)
+}
+
+// reloader re-reads the config files on SIGHUP or on POST to the admin endpoint and applies the changes that are
+// safe at runtime. A config with changes that need a restart is rejected as a whole, nothing is applied.
+type reloader struct {
+	mu       sync.Mutex
+	cfgFile  string
+	overlays []string
+	cfg      config.Config
+	flags    func(cfg *config.Config) // applies the CLI flags overriding the config, if any
+	logLevel zap.AtomicLevel
+	logger   *zap.SugaredLogger
+	appliers []reloadApplier
+}
+
+func newReloader(cfgFile string, overlays []string, cfg config.Config, logLevel zap.AtomicLevel, logger *zap.Logger) *reloader {
+	return &reloader{
+		cfgFile:  cfgFile,
+		overlays: overlays,
+		cfg:      cfg,
+		logLevel: logLevel,
+		logger:   logger.With(zap.String("module", "reload")).Sugar(),
+	}
+}
+
+func (rl *reloader) addApplier(validate func(cfg *config.Config) error, apply func(cfg *config.Config)) {
+	rl.appliers = append(rl.appliers, reloadApplier{validate: validate, apply: apply})
+}
+
+// current returns the config as last reloaded
+func (rl *reloader) current() config.Config {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.cfg
+}
+
+// reload reads the config files again and applies the changed keys, returning them
+func (rl *reloader) reload() ([]config.Change, error) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	next, err := config.New(rl.cfgFile, rl.overlays...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if rl.flags != nil {
+		rl.flags(&next)
+	}
+	changes := config.Diff(&rl.cfg, &next)
+	if err := config.CheckReload(changes); err != nil {
+		return changes, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	for _, a := range rl.appliers {
+		if err := a.validate(&next); err != nil {
+			return changes, err
+		}
+	}
+
+	rl.logLevel.SetLevel(config.ParseLogLevel(next.Common.LogLevel))
+	for _, a := range rl.appliers {
+		a.apply(&next)
+	}
+	rl.cfg = next
+	return changes, nil
+}
+
+func (rl *reloader) reloadAndLog(trigger string) ([]config.Change, error) {
+	changes, err := rl.reload()
+	if err != nil {
+		rl.logger.Errorf("Rejected config reload on %s: %v", trigger, err)
+		return changes, err
+	}
+	if len(changes) == 0 {
+		rl.logger.Infof("Reloaded config on %s, nothing changed", trigger)
+	}
+	for _, change := range changes {
+		rl.logger.Infof("Reloaded config on %s, %s", trigger, change)
+	}
+	return changes, nil
+}
+
+// start reloads the config on every SIGHUP and registers the admin endpoint on the metrics server, which is only
+// served while the metrics server is private
+func (rl *reloader) start(server *metrics.Server) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			_, _ = rl.reloadAndLog("SIGHUP")
+		}
+	}()
+
+	if !rl.cfg.Metrics.Private() {
+		rl.logger.Warnf("Not serving %s, it needs metrics auth or a loopback metrics host; reload with SIGHUP instead", reloadPath)
+	}
+	server.Handle(reloadPath, http.HandlerFunc(rl.serveHTTP))
+}
+
+type reloadResponse struct {
+	Changes []config.Change `json:"changes"`
+	Error   string          `json:"error,omitempty"`
+}
+
+func (rl *reloader) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// the endpoint changes the running config, so it is not served to anyone who can reach the metrics
+	if cfg := rl.current(); !cfg.Metrics.Private() {
+		http.NotFound(w, req)
+		return
+	}
+
+	changes, err := rl.reloadAndLog(reloadPath)
+	res := reloadResponse{Changes: changes}
+	status := http.StatusOK
+	if err != nil {
+		res.Error = err.Error()
+		status = http.StatusConflict
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/reporter"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// GetReplayCmd returns the CLI command replaying a reporter journal
+func GetReplayCmd() *cobra.Command {
+	var journalPath string
+	var cfgFile = ""
+	var cfgOverlays []string
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Replay a reporter journal against the reporter logic",
+		Long: "Feeds the block events of a journal back through the reporter, with clients reproducing the journaled " +
+			"BTC chain and Lorenzo responses, and reports every event whose submissions differ from the journaled ones.",
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cfg, err := config.New(cfgFile, cfgOverlays...)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			if err := cfg.Reporter.Validate(); err != nil {
+				return fmt.Errorf("invalid config in reporter: %w", err)
+			}
+			if len(journalPath) == 0 {
+				journalPath = cfg.Journal.Path
+			}
+
+			rootLogger, err := cfg.CreateLogger()
+			if err != nil {
+				return fmt.Errorf("failed to create logger: %w", err)
+			}
+
+			records, err := journal.ReadFiles(journalPath)
+			if err != nil {
+				return fmt.Errorf("failed to read journal: %w", err)
+			}
+
+			result, err := reporter.Replay(&cfg.Reporter, rootLogger, records)
+			if err != nil {
+				return fmt.Errorf("failed to replay journal: %w", err)
+			}
+
+			fmt.Printf("Replayed %d bootstraps and %d block events (%d records skipped)\n",
+				result.Bootstraps, result.BlockEvents, result.Skipped)
+			for _, d := range result.Divergences {
+				event := "connected"
+				if d.EventType == types.BlockDisconnected {
+					event = "disconnected"
+				}
+				fmt.Printf("%s %s block %d (%s):\n  recorded submissions: [%s]\n  replayed submissions: [%s]\n",
+					d.Time.Format("2006-01-02T15:04:05.000Z07:00"), event, d.Height, d.Hash,
+					strings.Join(d.Recorded, " "), strings.Join(d.Replayed, " "))
+				if d.Error != "" {
+					fmt.Printf("  replay required a bootstrap the journal does not show: %s\n", d.Error)
+				}
+			}
+			if len(result.Divergences) > 0 {
+				return fmt.Errorf("%w: %d block events diverged", types.ErrReplayDivergence, len(result.Divergences))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&journalPath, "journal", "", "path of the active journal file (defaults to journal.path in the config)")
+	cmd.Flags().StringVar(&cfgFile, "config", config.DefaultConfigFile(), "config file")
+	cmd.Flags().StringSliceVar(&cfgOverlays, "config-overlay", nil, "config files merged over the config file, in order")
+	return cmd
+}
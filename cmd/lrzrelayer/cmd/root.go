@@ -1,17 +1,29 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
+// tracingShutdownTimeout bounds how long the pending spans are flushed on shutdown
+const tracingShutdownTimeout = 5 * time.Second
+
+// metricsShutdownTimeout bounds how long the requests in flight to the metrics server are waited for on shutdown
+const metricsShutdownTimeout = 5 * time.Second
+
 func NewRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "lrzrelayer",
 		Short: "Lorenzo relayer",
 	}
 	rootCmd.AddCommand(
+		GetStartCmd(),
 		GetReporterCmd(),
 		GetBNBReporterCommand(),
+		GetReplayCmd(),
+		GetConfigCmd(),
+		GetAuditCmd(),
 	)
 
 	return rootCmd
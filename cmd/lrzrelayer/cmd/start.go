@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/alert"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/audit"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/bnbreporter"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/btcclient"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/reporter"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/signer"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/supervisor"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/tracing"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// GetStartCmd returns the CLI command running every reporter enabled in the config in one process
+func GetStartCmd() *cobra.Command {
+	var lorenzoKeyDir string
+	var cfgFile = ""
+	var cfgOverlays []string
+
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Run the reporters enabled in the config",
+		Long: "Runs the BTC and BNB reporters enabled in the config in one process, sharing the Lorenzo clients, " +
+			"the metrics registry and the admin server. A failing reporter is restarted with backoff while the others " +
+			"keep relaying, see the supervisor section of the config.",
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runReporters("start", cfgFile, cfgOverlays, lorenzoKeyDir, nil)
+		},
+	}
+	cmd.Flags().StringVar(&lorenzoKeyDir, "lorenzo-key-dir", "", "Directory of the Lorenzo key")
+	cmd.Flags().StringVar(&cfgFile, "config", config.DefaultConfigFile(), "config file")
+	cmd.Flags().StringSliceVar(&cfgOverlays, "config-overlay", nil, "config files merged over the config file, in order")
+	return cmd
+}
+
+// runReporters runs the given reporters, or the ones enabled in the config if none is given, under a supervisor
+// restarting the failed ones, until the process is interrupted or the supervisor gives up on a reporter.
+// It returns the failure the supervisor gave up on, if any.
+func runReporters(module string, cfgFile string, cfgOverlays []string, lorenzoKeyDir string, reporters []string) error {
+	cfg, err := config.New(cfgFile, cfgOverlays...)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	enabled := reporters
+	if len(enabled) == 0 {
+		enabled = cfg.EnabledReporters()
+	}
+	if len(enabled) == 0 {
+		return fmt.Errorf("%w: no reporter is enabled", types.ErrConfig)
+	}
+	if err := cfg.ValidateReporters(enabled...); err != nil {
+		return err
+	}
+
+	rootLogger, logLevel, err := cfg.Common.CreateReloadableLogger()
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+	logger := rootLogger.With(zap.String("module", module)).Sugar()
+
+	// apply the flags from CLI
+	applyFlags := func(cfg *config.Config) {
+		if len(lorenzoKeyDir) != 0 {
+			cfg.Lorenzo.KeyDirectory = lorenzoKeyDir
+		}
+	}
+	applyFlags(&cfg)
+
+	// one registry and one set of Lorenzo clients for all reporters. Reporters assigned the same key
+	// share its account sequence instead of racing for it
+	registry := prometheus.NewRegistry()
+
+	// notify operators of stalls, reorgs and failures, if enabled
+	alerts := alert.New(&cfg.Alerts, rootLogger)
+	alerts.Start()
+
+	signers, err := signer.NewGroup(&cfg.Signers, &cfg.Lorenzo, rootLogger, metrics.NewSignerMetrics(registry), alerts)
+	if err != nil {
+		return fmt.Errorf("failed to create Lorenzo signers: %w", err)
+	}
+
+	// open the event journal, if enabled
+	eventJournal, err := journal.New(&cfg.Journal, rootLogger)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	// open the audit log of the header txs, if enabled
+	auditLog, err := audit.New(&cfg.Audit, rootLogger)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w: %w", types.ErrConfig, err)
+	}
+
+	// export the spans of the reporter stages, if enabled
+	stopTracing, err := tracing.Start(&cfg.Tracing, rootLogger)
+	if err != nil {
+		return fmt.Errorf("failed to start tracing: %w", err)
+	}
+
+	configReloader := newReloader(cfgFile, cfgOverlays, cfg, logLevel, rootLogger)
+	configReloader.flags = applyFlags
+	configReloader.addApplier(func(next *config.Config) error {
+		return next.Signers.Validate()
+	}, func(next *config.Config) {
+		signers.UpdateConfig(&next.Signers)
+	})
+	configReloader.addApplier(func(next *config.Config) error {
+		return next.Alerts.Validate()
+	}, func(next *config.Config) {
+		alerts.UpdateConfig(&next.Alerts)
+	})
+
+	// the Lorenzo clients and metrics of a reporter outlive the instances restarted by the supervisor
+	sup := supervisor.New(&cfg.Supervisor, rootLogger, metrics.NewSupervisorMetrics(registry))
+	var (
+		services int
+		poolErr  error
+	)
+	for _, name := range enabled {
+		pool, err := signers.Pool(name)
+		if err != nil {
+			logger.Errorf("Failed to open the Lorenzo client of %s, the other reporters keep running: %v", name, err)
+			poolErr = err
+			continue
+		}
+		switch name {
+		case config.SignerReporter:
+			reporterMetrics := metrics.NewReporterMetricsIn(registry)
+			var current atomic.Pointer[btcService]
+			configReloader.addApplier(func(next *config.Config) error {
+				return next.ValidateReporters(config.SignerReporter)
+			}, func(next *config.Config) {
+				if s := current.Load(); s != nil {
+					s.UpdateConfig(&next.Reporter)
+				}
+				alerts.WatchStall(journal.ModuleReporter, next.Alerts.BTCStallAfter)
+			})
+			alerts.WatchStall(journal.ModuleReporter, cfg.Alerts.BTCStallAfter)
+			sup.Add(name, func() (supervisor.Service, error) {
+				cfg := configReloader.current()
+				s, err := newBTCReporter(&cfg, pool, reporterMetrics, eventJournal, auditLog, alerts, rootLogger)
+				if err != nil {
+					return nil, err
+				}
+				current.Store(s)
+				return s, nil
+			})
+
+		case config.SignerBNBReporter:
+			bnbMetrics := metrics.NewBNBReporterMetrics(registry)
+			var current atomic.Pointer[bnbreporter.BNBReporter]
+			configReloader.addApplier(func(next *config.Config) error {
+				return next.ValidateReporters(config.SignerBNBReporter)
+			}, func(next *config.Config) {
+				if r := current.Load(); r != nil {
+					r.UpdateConfig(&next.BNBReporter)
+				}
+				alerts.WatchStall(journal.ModuleBNBReporter, next.Alerts.BNBStallAfter)
+			})
+			alerts.WatchStall(journal.ModuleBNBReporter, cfg.Alerts.BNBStallAfter)
+			sup.Add(name, func() (supervisor.Service, error) {
+				cfg := configReloader.current()
+				r, err := bnbreporter.New(rootLogger, pool, &cfg.BNBReporter, bnbMetrics, eventJournal, auditLog, alerts)
+				if err != nil {
+					return nil, err
+				}
+				current.Store(r)
+				return r, nil
+			})
+		}
+		services++
+	}
+	if services == 0 {
+		return fmt.Errorf("failed to create any of the enabled reporters: %w", poolErr)
+	}
+
+	var (
+		escalatedMu sync.Mutex
+		escalated   error
+	)
+	escalate := func(err error) {
+		escalatedMu.Lock()
+		escalated = err
+		escalatedMu.Unlock()
+		requestShutdown()
+	}
+
+	// start Prometheus metrics server, which also serves the admin endpoint reloading the safe subset of the
+	// config like SIGHUP does. It is started before the reporters, so that none runs if it fails to start
+	if err := startMetricsServer(&cfg.Metrics, registry, configReloader, rootLogger, escalate); err != nil {
+		return err
+	}
+
+	// check the balances of the signers before the reporters start broadcasting
+	signers.Start()
+
+	// run the reporters, restarting the failed ones until the supervisor gives up on one
+	sup.Start()
+	go func() {
+		escalate(<-sup.Escalated())
+	}()
+
+	// SIGINT handling stuff
+	addInterruptHandler(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := stopTracing(ctx); err != nil {
+			logger.Errorf("Failed to flush spans: %v", err)
+		}
+	})
+	// registered early so that it runs late, sending the notifications of the shutdown
+	addInterruptHandler(alerts.Stop)
+	addInterruptHandler(func() {
+		if err := eventJournal.Close(); err != nil {
+			logger.Errorf("Failed to close journal: %v", err)
+		}
+	})
+	addInterruptHandler(func() {
+		if err := auditLog.Close(); err != nil {
+			logger.Errorf("Failed to close audit log: %v", err)
+		}
+	})
+	addInterruptHandler(func() {
+		rootLogger.Info("Stopping Lorenzo signers...")
+		if err := signers.Stop(); err != nil {
+			logger.Errorf("Failed to stop Lorenzo signers: %v", err)
+		}
+	})
+	addInterruptHandler(func() {
+		rootLogger.Info("Stopping reporters...")
+		sup.Stop()
+		rootLogger.Info("Reporters shutdown")
+	})
+
+	<-interruptHandlersDone
+	rootLogger.Info("Shutdown complete")
+
+	// exit with the code of the failure the supervisor gave up on, if any
+	escalatedMu.Lock()
+	defer escalatedMu.Unlock()
+	return escalated
+}
+
+// btcService is the BTC reporter together with the BTC client it owns, restarted as one by the supervisor
+type btcService struct {
+	*reporter.Reporter
+	btcClient *btcclient.Client
+}
+
+func (s *btcService) Stop() {
+	s.Reporter.Stop()
+	s.btcClient.Stop()
+}
+
+func (s *btcService) WaitForShutdown() {
+	s.Reporter.WaitForShutdown()
+	s.btcClient.WaitForShutdown()
+}
+
+// newBTCReporter creates the BTC reporter and its BTC client
+func newBTCReporter(
+	cfg *config.Config,
+	lorenzoClient *signer.Pool,
+	reporterMetrics *metrics.ReporterMetrics,
+	eventJournal *journal.Journal,
+	auditLog *audit.Log,
+	alerts *alert.Dispatcher,
+	rootLogger *zap.Logger,
+) (*btcService, error) {
+	// Note that vigilant reporter needs to subscribe to new BTC blocks
+	btcClient, err := btcclient.NewWithBlockSubscriber(&cfg.BTC, cfg.Common.RetrySleepTime, cfg.Common.MaxRetrySleepTime, rootLogger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open BTC client: %w", err)
+	}
+	r, err := reporter.New(
+		&cfg.Reporter,
+		rootLogger,
+		btcClient,
+		lorenzoClient,
+		cfg.Common.RetrySleepTime,
+		cfg.Common.MaxRetrySleepTime,
+		reporterMetrics,
+		eventJournal,
+		auditLog,
+		alerts,
+	)
+	if err != nil {
+		btcClient.Stop()
+		btcClient.WaitForShutdown()
+		return nil, err
+	}
+	return &btcService{Reporter: r, btcClient: btcClient}, nil
+}
@@ -74,6 +74,14 @@ func mainInterruptHandler() {
 	}
 }
 
+// requestShutdown runs the interrupt handlers as if the process was interrupted
+func requestShutdown() {
+	select {
+	case simulateInterruptChannel <- struct{}{}:
+	default:
+	}
+}
+
 // AddInterruptHandler adds a handler to call when a SIGINT (Ctrl+C) is
 // received.
 func addInterruptHandler(handler func()) {
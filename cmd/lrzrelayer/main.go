@@ -1,25 +1,26 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/Lorenzo-Protocol/lorenzo/v3/app/params"
 
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/cmd/lrzrelayer/cmd"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
 func main() {
 	params.SetAddressPrefixes()
 
+	os.Exit(run())
+}
+
+// run executes the root command. The error it returns decides the exit code, see types.ExitCode.
+func run() int {
 	rootCmd := cmd.NewRootCmd()
 
 	if err := rootCmd.Execute(); err != nil {
-		switch e := err.(type) {
-		// TODO: dedicated error codes for lrzrelayer
-		default:
-			fmt.Print(e.Error())
-			os.Exit(1)
-		}
+		return types.ExitCode(err)
 	}
+	return types.ExitOK
 }
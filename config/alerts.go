@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultAlertsTimeout           = 10 * time.Second
+	defaultAlertsDedupWindow       = time.Hour
+	defaultAlertsMaxPerHour        = 20
+	defaultAlertsBTCStallAfter     = 2 * time.Hour
+	defaultAlertsBNBStallAfter     = 5 * time.Minute
+	defaultAlertsReorgDepth        = 3
+	defaultAlertsBootstrapFailures = 3
+)
+
+// AlertsConfig defines the notifications sent to operators on stalls, reorgs, consistency failures,
+// low balances and repeated bootstrap failures
+type AlertsConfig struct {
+	// Enabled turns the notifications on
+	Enabled bool `mapstructure:"enabled"`
+	// WebhookURL receives every notification as a JSON POST
+	WebhookURL string `mapstructure:"webhook-url"`
+	// Timeout bounds every POST to the webhook
+	Timeout time.Duration `mapstructure:"timeout"`
+	// DedupWindow is how long a notification of the same condition is not repeated
+	DedupWindow time.Duration `mapstructure:"dedup-window"`
+	// MaxPerHour caps the notifications sent in any hour, the others are dropped
+	MaxPerHour int `mapstructure:"max-per-hour"`
+	// BTCStallAfter and BNBStallAfter are how long a reporter may relay nothing before it is reported stalled
+	BTCStallAfter time.Duration `mapstructure:"btc-stall-after"`
+	BNBStallAfter time.Duration `mapstructure:"bnb-stall-after"`
+	// ReorgDepth is the number of blocks a BTC reorg has to replace to be notified
+	ReorgDepth int `mapstructure:"reorg-depth"`
+	// BootstrapFailures is the number of consecutive failed bootstraps that is notified
+	BootstrapFailures int `mapstructure:"bootstrap-failures"`
+}
+
+func (cfg *AlertsConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if u, err := url.Parse(cfg.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("webhook-url must be an http(s) URL")
+	}
+	if cfg.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+	if cfg.DedupWindow < 0 {
+		return errors.New("dedup-window can't be negative")
+	}
+	if cfg.MaxPerHour <= 0 {
+		return errors.New("max-per-hour must be positive")
+	}
+	if cfg.BTCStallAfter <= 0 || cfg.BNBStallAfter <= 0 {
+		return errors.New("btc-stall-after and bnb-stall-after must be positive")
+	}
+	if cfg.ReorgDepth <= 0 {
+		return errors.New("reorg-depth must be positive")
+	}
+	if cfg.BootstrapFailures <= 0 {
+		return errors.New("bootstrap-failures must be positive")
+	}
+	return nil
+}
+
+func DefaultAlertsConfig() AlertsConfig {
+	return AlertsConfig{
+		Enabled:           false,
+		Timeout:           defaultAlertsTimeout,
+		DedupWindow:       defaultAlertsDedupWindow,
+		MaxPerHour:        defaultAlertsMaxPerHour,
+		BTCStallAfter:     defaultAlertsBTCStallAfter,
+		BNBStallAfter:     defaultAlertsBNBStallAfter,
+		ReorgDepth:        defaultAlertsReorgDepth,
+		BootstrapFailures: defaultAlertsBootstrapFailures,
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+)
+
+var defaultAuditPath = filepath.Join(defaultAppDataDir, "audit", "audit.jsonl")
+
+// AuditConfig defines the hash-chained log of the header txs sent to Lorenzo
+type AuditConfig struct {
+	// Enabled turns the audit log on
+	Enabled bool `mapstructure:"enabled"`
+	// Path of the audit log. It is never rotated, and processes running at the same time need different paths.
+	Path string `mapstructure:"path"`
+}
+
+func (cfg *AuditConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Path == "" {
+		return errors.New("path cannot be empty")
+	}
+	return nil
+}
+
+func DefaultAuditConfig() AuditConfig {
+	return AuditConfig{
+		Enabled: false,
+		Path:    defaultAuditPath,
+	}
+}
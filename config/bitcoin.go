@@ -3,7 +3,9 @@ package config
 import (
 	"errors"
 	"os"
+	"time"
 
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/netparams"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
@@ -18,6 +20,10 @@ type BTCConfig struct {
 	ReconnectAttempts int                       `mapstructure:"reconnect-attempts"`
 	BtcBackend        types.SupportedBtcBackend `mapstructure:"btc-backend"`
 	ZmqSeqEndpoint    string                    `mapstructure:"zmq-seq-endpoint"`
+	// RPCTimeout is the deadline of every RPC call to the BTC node (0 waits as long as the caller does)
+	RPCTimeout time.Duration `mapstructure:"rpc-timeout"`
+	// Networks resolves NetParams, set from the networks section by New; nil knows the built-in networks only
+	Networks *netparams.Registry `mapstructure:"-"`
 }
 
 func (cfg *BTCConfig) Validate() error {
@@ -25,7 +31,11 @@ func (cfg *BTCConfig) Validate() error {
 		return errors.New("reconnect-attempts must be non-negative")
 	}
 
-	if _, ok := types.GetValidNetParams()[cfg.NetParams]; !ok {
+	if cfg.RPCTimeout < 0 {
+		return errors.New("rpc-timeout can't be negative")
+	}
+
+	if !cfg.Networks.Exists(cfg.NetParams) {
 		return errors.New("invalid net params")
 	}
 
@@ -49,6 +59,7 @@ const (
 	DefaultBtcNodeRpcUser = "rpcuser"
 	DefaultBtcNodeRpcPass = "rpcpass"
 	DefaultZmqSeqEndpoint = "tcp://127.0.0.1:29000"
+	DefaultBTCRPCTimeout  = 30 * time.Second
 )
 
 func DefaultBTCConfig() BTCConfig {
@@ -62,6 +73,7 @@ func DefaultBTCConfig() BTCConfig {
 		Password:          DefaultBtcNodeRpcPass,
 		ReconnectAttempts: 3,
 		ZmqSeqEndpoint:    DefaultZmqSeqEndpoint,
+		RPCTimeout:        DefaultBTCRPCTimeout,
 	}
 }
 
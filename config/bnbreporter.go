@@ -3,12 +3,27 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 type BNBReporterConfig struct {
+	// Enabled is whether `lrzrelayer start` runs the BNB reporter
+	Enabled     bool   `mapstructure:"enabled"`
 	RpcUrl      string `mapstructure:"rpc_url"`
 	DelayBlocks uint64 `mapstructure:"delay_blocks"`
 	BaseHeight  uint64 `mapstructure:"base_height"`
+	// PollInterval is how often the BNB tip is polled while the reporter waits for new blocks, 1s if unset
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// RPCTimeout is the deadline of every RPC call to the BNB node (0 waits as long as the caller does)
+	RPCTimeout time.Duration `mapstructure:"rpc_timeout"`
+	// StallAfter is how long the reporter may relay nothing before the watchdog reconnects to the BNB node
+	// and bootstraps again; 0 disables the watchdog
+	StallAfter time.Duration `mapstructure:"stall_after"`
+	// Checkpoints are trusted BNB block hashes; the reporter refuses to bootstrap or relay if the BNB node disagrees
+	Checkpoints []CheckpointConfig `mapstructure:"checkpoints"`
 }
 
 func (cfg *BNBReporterConfig) Validate() error {
@@ -21,6 +36,49 @@ func (cfg *BNBReporterConfig) Validate() error {
 	if cfg.DelayBlocks == 0 {
 		return errors.New("BNB delay blocks cannot be 0")
 	}
+	if cfg.PollInterval < 0 {
+		return errors.New("BNB poll interval cannot be negative")
+	}
+	if cfg.RPCTimeout < 0 {
+		return errors.New("BNB rpc timeout cannot be negative")
+	}
+	if cfg.StallAfter < 0 {
+		return errors.New("BNB stall after cannot be negative")
+	}
+	if err := validateCheckpoints(cfg.Checkpoints, func(hash string) error {
+		b, err := hexutil.Decode(hash)
+		if err != nil {
+			return err
+		}
+		if len(b) != common.HashLength {
+			return fmt.Errorf("expected %d bytes, got %d", common.HashLength, len(b))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
 
 	return nil
 }
+
+const (
+	defaultBNBRpcUrl        = "https://bsc-testnet.bnbchain.org"
+	defaultBNBMainnetRpcUrl = "https://bsc-dataseed.bnbchain.org"
+	defaultBNBDelayBlocks   = 15
+	defaultBNBPollInterval  = time.Second
+	defaultBNBRPCTimeout    = 10 * time.Second
+	defaultBNBStallAfter    = 2 * time.Minute
+)
+
+// DefaultBNBReporterConfig returns the BNB reporter config for BSC testnet. BaseHeight is left unset, it has to be
+// the height of the base header of Lorenzo's BNB light client.
+func DefaultBNBReporterConfig() BNBReporterConfig {
+	return BNBReporterConfig{
+		RpcUrl:       defaultBNBRpcUrl,
+		DelayBlocks:  defaultBNBDelayBlocks,
+		PollInterval: defaultBNBPollInterval,
+		RPCTimeout:   defaultBNBRPCTimeout,
+		StallAfter:   defaultBNBStallAfter,
+		Checkpoints:  []CheckpointConfig{},
+	}
+}
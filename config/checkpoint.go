@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+)
+
+// CheckpointConfig pins the block hash a source chain is expected to have at a given height.
+type CheckpointConfig struct {
+	Height uint64 `mapstructure:"height"`
+	Hash   string `mapstructure:"hash"`
+}
+
+// validateCheckpoints checks that every checkpoint has a well-formed hash and that no height is pinned twice
+func validateCheckpoints(checkpoints []CheckpointConfig, validateHash func(string) error) error {
+	seen := make(map[uint64]struct{}, len(checkpoints))
+	for _, cp := range checkpoints {
+		if _, ok := seen[cp.Height]; ok {
+			return fmt.Errorf("duplicate checkpoint at height %d", cp.Height)
+		}
+		seen[cp.Height] = struct{}{}
+
+		if err := validateHash(cp.Hash); err != nil {
+			return fmt.Errorf("invalid checkpoint hash at height %d: %w", cp.Height, err)
+		}
+	}
+	return nil
+}
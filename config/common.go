@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"go.uber.org/zap"
@@ -10,14 +11,34 @@ import (
 const (
 	defaultRetrySleepTime    = 5 * time.Second
 	defaultMaxRetrySleepTime = 5 * time.Minute
+	defaultLogFileMaxSizeMB  = 100
+	defaultLogFileMaxBackups = 10
+	defaultLogFileMaxAgeDays = 30
+	defaultLogSampleInterval = 0
 )
 
+// logLevels are the accepted log levels
+var logLevels = []string{"debug", "info", "warn", "error", "panic", "fatal"}
+
 // CommonConfig defines the server's basic configuration
 type CommonConfig struct {
 	// LogFormat is the format of the log (json|auto|console|logfmt)
 	LogFormat string `mapstructure:"log-format"`
-	// LogLevel is the log level (debug|warn|error|panic|fatal)
+	// LogLevel is the log level (debug|info|warn|error|panic|fatal)
 	LogLevel string `mapstructure:"log-level"`
+	// LogLevels override LogLevel for the modules named by the module field of the log, e.g., zmq or btcclient
+	LogLevels map[string]string `mapstructure:"log-levels"`
+	// LogFile is a file the log is written to in addition to stderr, none if empty. It is rotated like the journal.
+	LogFile string `mapstructure:"log-file"`
+	// LogFileMaxSizeMB is the size in megabytes at which the log file is rotated
+	LogFileMaxSizeMB int `mapstructure:"log-file-max-size-mb"`
+	// LogFileMaxBackups is the number of rotated log files to keep (0 keeps all of them)
+	LogFileMaxBackups int `mapstructure:"log-file-max-backups"`
+	// LogFileMaxAgeDays is the number of days to keep rotated log files (0 keeps them forever)
+	LogFileMaxAgeDays int `mapstructure:"log-file-max-age-days"`
+	// LogSampleInterval is how often a debug line of a module with the same message, e.g., of a polling loop, is logged
+	// at most. 0 logs every debug line.
+	LogSampleInterval time.Duration `mapstructure:"log-sample-interval"`
 	// Backoff interval for the first retry.
 	RetrySleepTime time.Duration `mapstructure:"retry-sleep-time"`
 	// Maximum backoff interval between retries. Exponential backoff leads to interval increase.
@@ -38,8 +59,25 @@ func (cfg *CommonConfig) Validate() error {
 	if !isOneOf(cfg.LogFormat, []string{"json", "auto", "console", "logfmt"}) {
 		return errors.New("log-format is not one of json|auto|console|logfmt")
 	}
-	if !isOneOf(cfg.LogLevel, []string{"debug", "warn", "error", "panic", "fatal"}) {
-		return errors.New("log-level is not one of debug|warn|error|panic|fatal")
+	if !isOneOf(cfg.LogLevel, logLevels) {
+		return errors.New("log-level is not one of debug|info|warn|error|panic|fatal")
+	}
+	for module, level := range cfg.LogLevels {
+		if !isOneOf(level, logLevels) {
+			return fmt.Errorf("log-levels of %s is not one of debug|info|warn|error|panic|fatal", module)
+		}
+	}
+	if cfg.LogFile != "" && cfg.LogFileMaxSizeMB <= 0 {
+		return errors.New("log-file-max-size-mb must be positive")
+	}
+	if cfg.LogFileMaxBackups < 0 {
+		return errors.New("log-file-max-backups can't be negative")
+	}
+	if cfg.LogFileMaxAgeDays < 0 {
+		return errors.New("log-file-max-age-days can't be negative")
+	}
+	if cfg.LogSampleInterval < 0 {
+		return errors.New("log-sample-interval can't be negative")
 	}
 	if cfg.RetrySleepTime < 0 {
 		return errors.New("retry-sleep-time can't be negative")
@@ -51,13 +89,26 @@ func (cfg *CommonConfig) Validate() error {
 }
 
 func (cfg *CommonConfig) CreateLogger() (*zap.Logger, error) {
-	return NewRootLogger(cfg.LogFormat, cfg.LogLevel)
+	return cfg.newLogger(zap.NewAtomicLevelAt(ParseLogLevel(cfg.LogLevel)))
+}
+
+// CreateReloadableLogger creates a logger whose level can be changed at runtime through the returned level.
+// The levels of the modules in LogLevels stay as configured.
+func (cfg *CommonConfig) CreateReloadableLogger() (*zap.Logger, zap.AtomicLevel, error) {
+	level := zap.NewAtomicLevelAt(ParseLogLevel(cfg.LogLevel))
+	logger, err := cfg.newLogger(level)
+	return logger, level, err
 }
 
 func DefaultCommonConfig() CommonConfig {
 	return CommonConfig{
 		LogFormat:         "auto",
 		LogLevel:          "debug",
+		LogLevels:         map[string]string{},
+		LogFileMaxSizeMB:  defaultLogFileMaxSizeMB,
+		LogFileMaxBackups: defaultLogFileMaxBackups,
+		LogFileMaxAgeDays: defaultLogFileMaxAgeDays,
+		LogSampleInterval: defaultLogSampleInterval,
 		RetrySleepTime:    defaultRetrySleepTime,
 		MaxRetrySleepTime: defaultMaxRetrySleepTime,
 	}
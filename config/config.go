@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"time"
 
 	lrzcfg "github.com/Lorenzo-Protocol/lorenzo-sdk/v3/config"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/netparams"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
 const (
@@ -30,18 +35,22 @@ type Config struct {
 	Metrics     MetricsConfig        `mapstructure:"metrics"`
 	Reporter    ReporterConfig       `mapstructure:"reporter"`
 	BNBReporter BNBReporterConfig    `mapstructure:"bnbreporter"`
+	Journal     JournalConfig        `mapstructure:"journal"`
+	Audit       AuditConfig          `mapstructure:"audit"`
+	Signers     SignersConfig        `mapstructure:"signers"`
+	Supervisor  SupervisorConfig     `mapstructure:"supervisor"`
+	Alerts      AlertsConfig         `mapstructure:"alerts"`
+	Tracing     TracingConfig        `mapstructure:"tracing"`
+	// Networks are custom Bitcoin networks by name, usable as net params next to the built-in ones
+	Networks map[string]NetworkConfig `mapstructure:"networks"`
 }
 
+// Validate checks the sections shared by all commands. The sections of the reporters are checked by ValidateReporters.
 func (cfg *Config) Validate() error {
 	if err := cfg.Common.Validate(); err != nil {
 		return fmt.Errorf("invalid config in common: %w", err)
 	}
 
-	// move to reporter
-	//if err := cfg.BTC.Validate(); err != nil {
-	//	return fmt.Errorf("invalid config in btc: %w", err)
-	//}
-
 	if err := cfg.Lorenzo.Validate(); err != nil {
 		return fmt.Errorf("invalid config in lorenzo: %w", err)
 	}
@@ -50,15 +59,78 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("invalid config in metrics: %w", err)
 	}
 
-	// Move to reporter
-	//if err := cfg.Reporter.Validate(); err != nil {
-	//	return fmt.Errorf("invalid config in reporter: %w", err)
-	//}
-	// Move to bnbreporter
-	//if err := cfg.BNBReporter.Validate(); err != nil {
-	//	return fmt.Errorf("invalid config in bnbreporter: %w", err)
-	//}
+	if err := cfg.Journal.Validate(); err != nil {
+		return fmt.Errorf("invalid config in journal: %w", err)
+	}
+
+	if err := cfg.Audit.Validate(); err != nil {
+		return fmt.Errorf("invalid config in audit: %w", err)
+	}
+
+	if err := cfg.Signers.Validate(); err != nil {
+		return fmt.Errorf("invalid config in signers: %w", err)
+	}
+
+	if err := cfg.Supervisor.Validate(); err != nil {
+		return fmt.Errorf("invalid config in supervisor: %w", err)
+	}
+
+	if err := cfg.Alerts.Validate(); err != nil {
+		return fmt.Errorf("invalid config in alerts: %w", err)
+	}
+
+	if err := cfg.Tracing.Validate(); err != nil {
+		return fmt.Errorf("invalid config in tracing: %w", err)
+	}
+
+	if err := validateNetworks(cfg.Networks); err != nil {
+		return fmt.Errorf("invalid config in networks: %w", err)
+	}
+
+	return nil
+}
+
+// EnabledReporters returns the reporters (reporter|bnbreporter) run by `lrzrelayer start`
+func (cfg *Config) EnabledReporters() []string {
+	var reporters []string
+	if cfg.Reporter.Enabled {
+		reporters = append(reporters, SignerReporter)
+	}
+	if cfg.BNBReporter.Enabled {
+		reporters = append(reporters, SignerBNBReporter)
+	}
+	return reporters
+}
 
+// ValidateReporters checks the sections used by the given reporters (reporter|bnbreporter). Errors wrap types.ErrConfig.
+func (cfg *Config) ValidateReporters(reporters ...string) error {
+	if err := cfg.validateReporters(reporters); err != nil {
+		return fmt.Errorf("%w: %w", types.ErrConfig, err)
+	}
+	return nil
+}
+
+func (cfg *Config) validateReporters(reporters []string) error {
+	for _, reporter := range reporters {
+		switch reporter {
+		case SignerReporter:
+			if err := cfg.BTC.Validate(); err != nil {
+				return fmt.Errorf("invalid config in btc: %w", err)
+			}
+			if err := cfg.Reporter.Validate(); err != nil {
+				return fmt.Errorf("invalid config in reporter: %w", err)
+			}
+			if cfg.BTC.NetParams != cfg.Reporter.NetParams {
+				return fmt.Errorf("net params of btc (%s) and reporter (%s) differ", cfg.BTC.NetParams, cfg.Reporter.NetParams)
+			}
+		case SignerBNBReporter:
+			if err := cfg.BNBReporter.Validate(); err != nil {
+				return fmt.Errorf("invalid config in bnbreporter: %w", err)
+			}
+		default:
+			return fmt.Errorf("unknown reporter %q", reporter)
+		}
+	}
 	return nil
 }
 
@@ -70,24 +142,112 @@ func DefaultConfigFile() string {
 	return defaultConfigFile
 }
 
-// New returns a fully parsed Config object from a given file directory
-func New(configFile string) (Config, error) {
-	if _, err := os.Stat(configFile); err == nil { // the given file exists, parse it
-		viper.SetConfigFile(configFile)
-		if err := viper.ReadInConfig(); err != nil {
+// DefaultConfig returns the default config for a bitcoind node on the given built-in Bitcoin network
+func DefaultConfig(network string) (Config, error) {
+	if !netparams.IsBuiltin(network) {
+		return Config{}, fmt.Errorf("unknown network %q", network)
+	}
+
+	cfg := Config{
+		Common:      DefaultCommonConfig(),
+		BTC:         DefaultBTCConfig(),
+		Lorenzo:     defaultLorenzoConfig(),
+		Metrics:     DefaultMetricsConfig(),
+		Reporter:    DefaultReporterConfig(),
+		BNBReporter: DefaultBNBReporterConfig(),
+		Journal:     DefaultJournalConfig(),
+		Audit:       DefaultAuditConfig(),
+		Signers:     DefaultSignersConfig(),
+		Supervisor:  DefaultSupervisorConfig(),
+		Alerts:      DefaultAlertsConfig(),
+		Tracing:     DefaultTracingConfig(),
+		Networks:    map[string]NetworkConfig{},
+	}
+	cfg.BTC.NetParams = network
+	cfg.BTC.Endpoint = "localhost:" + bitcoindRPCPorts[network]
+	cfg.BTC.DisableClientTLS = true
+	cfg.BTC.BtcBackend = types.Bitcoind
+	cfg.Reporter.NetParams = network
+	if network == types.BtcMainnet.String() {
+		cfg.BNBReporter.RpcUrl = defaultBNBMainnetRpcUrl
+	}
+	return cfg, nil
+}
+
+// bitcoindRPCPorts are the default RPC ports of bitcoind, and of btcd for simnet, by network
+var bitcoindRPCPorts = map[string]string{
+	types.BtcMainnet.String():  "8332",
+	types.BtcTestnet.String():  "18332",
+	types.BtcTestnet4.String(): "48332",
+	types.BtcSignet.String():   "38332",
+	types.BtcRegtest.String():  "18443",
+	types.BtcSimnet.String():   "18556",
+}
+
+func defaultLorenzoConfig() lrzcfg.LorenzoConfig {
+	return lrzcfg.LorenzoConfig{
+		Key:            "node0",
+		RPCAddr:        "http://localhost:26657",
+		AccountPrefix:  "lrz",
+		KeyringBackend: "test",
+		GasAdjustment:  1.2,
+		GasPrices:      "2ulrz",
+		KeyDirectory:   filepath.Join(defaultAppDataDir, "keys"),
+		Timeout:        20 * time.Second,
+		OutputFormat:   "json",
+		SignModeStr:    "direct",
+	}
+}
+
+// New returns a fully parsed Config object from a given file, merged with the given overlay files in order and
+// overridden by LRZRELAYER_* environment variables. Keys can be read from secret files, see readSecretFiles.
+// Each call uses its own viper instance, so several configs can be loaded in one process.
+// Errors wrap types.ErrConfig.
+func New(configFile string, overlays ...string) (Config, error) {
+	cfg, err := load(configFile, overlays...)
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %w", types.ErrConfig, err)
+	}
+	return cfg, nil
+}
+
+func load(configFile string, overlays ...string) (Config, error) {
+	v := viper.New()
+	for i, file := range append([]string{configFile}, overlays...) {
+		if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) { // the given config file does not exist, return error
+			return Config{}, fmt.Errorf("no config file found at %s", file)
+		} else if err != nil { // other errors
 			return Config{}, err
 		}
-		var cfg Config
-		if err := viper.Unmarshal(&cfg); err != nil {
-			return Config{}, err
+
+		v.SetConfigFile(file)
+		read := v.MergeInConfig
+		if i == 0 {
+			read = v.ReadInConfig
 		}
-		if err := cfg.Validate(); err != nil {
-			return Config{}, err
+		if err := read(); err != nil {
+			return Config{}, fmt.Errorf("failed to read config file %s: %w", file, err)
 		}
-		return cfg, err
-	} else if errors.Is(err, os.ErrNotExist) { // the given config file does not exist, return error
-		return Config{}, fmt.Errorf("no config file found at %s", configFile)
-	} else { // other errors
+	}
+
+	keys := configKeys(reflect.TypeOf(Config{}), "")
+	if err := bindEnvs(v, keys); err != nil {
+		return Config{}, err
+	}
+	if err := readSecretFiles(v, keys); err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return Config{}, err
+	}
+	// the sections naming a network are validated against the custom ones
+	if err := cfg.ResolveNetworks(); err != nil {
+		return Config{}, fmt.Errorf("invalid config in networks: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
 		return Config{}, err
 	}
+	return cfg, nil
 }
@@ -1,3 +1,131 @@
 package config_test
 
-// TODO: tests on config
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
+func TestNewLayers(t *testing.T) {
+	dir := t.TempDir()
+	overlay := filepath.Join(dir, "overlay.yml")
+	if err := os.WriteFile(overlay, []byte("reporter:\n  delay_blocks: 5\nbtc:\n  username: overlayuser\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(dir, "password")
+	if err := os.WriteFile(secret, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	base := filepath.Join("..", "sample-lrzrelayer.yml")
+	cfg, err := config.New(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Reporter.DelayBlocks != 3 || cfg.BTC.Username != "rpcuser" || cfg.BTC.Password != "rpcpass" {
+		t.Fatalf("unexpected base config: %d %s %s", cfg.Reporter.DelayBlocks, cfg.BTC.Username, cfg.BTC.Password)
+	}
+
+	t.Setenv(config.EnvName("btc.username"), "envuser")
+	t.Setenv(config.EnvName("btc.password_file"), secret)
+	t.Setenv(config.EnvName("reporter.btc_cache_size"), "2000")
+	cfg, err = config.New(base, overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Reporter.DelayBlocks != 5 || cfg.Reporter.MaxHeadersInMsg != 100 {
+		t.Fatalf("expected the overlay to be merged into the base config, got %d %d", cfg.Reporter.DelayBlocks, cfg.Reporter.MaxHeadersInMsg)
+	}
+	if cfg.BTC.Username != "envuser" || cfg.Reporter.BTCCacheSize != 2000 {
+		t.Fatalf("expected environment variables to override the files, got %s %d", cfg.BTC.Username, cfg.Reporter.BTCCacheSize)
+	}
+	if cfg.BTC.Password != "s3cret" {
+		t.Fatalf("expected the password from the secret file, got %q", cfg.BTC.Password)
+	}
+
+	if _, err := config.New(base, filepath.Join(dir, "missing.yml")); err == nil {
+		t.Fatal("expected error on missing overlay")
+	}
+}
+
+func TestDiffCheckReload(t *testing.T) {
+	current, err := config.New(filepath.Join("..", "sample-lrzrelayer.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := current
+	next.Reporter.DelayBlocks++
+	next.Common.LogLevel = "warn"
+	changes := config.Diff(&current, &next)
+	if len(changes) != 2 || changes[0].Key != "common.log-level" || changes[1].Key != "reporter.delay_blocks" {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+	if err := config.CheckReload(changes); err != nil {
+		t.Fatalf("expected reloadable changes, got %v", err)
+	}
+
+	next.BTC.Endpoint = "localhost:1"
+	next.BTC.Password = "other"
+	changes = config.Diff(&current, &next)
+	if err := config.CheckReload(changes); err == nil {
+		t.Fatal("expected endpoint and password changes to need a restart")
+	}
+	for _, change := range changes {
+		if change.Key == "btc.password" && (change.Old != "***" || change.New != "***") {
+			t.Fatalf("expected the password to be masked, got %v", change)
+		}
+	}
+}
+
+func TestDefaultConfigRoundTrip(t *testing.T) {
+	for _, network := range []string{"mainnet", "testnet", "testnet4", "signet", "regtest", "simnet"} {
+		cfg, err := config.DefaultConfig(network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.BTC.Password = "s3cret"
+		var written bytes.Buffer
+		if err := cfg.WriteYAML(&written, false); err != nil {
+			t.Fatal(err)
+		}
+		file := filepath.Join(t.TempDir(), "lrzrelayer.yml")
+		if err := os.WriteFile(file, written.Bytes(), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		loaded, err := config.New(file)
+		if err != nil {
+			t.Fatalf("failed to load the default %s config: %v", network, err)
+		}
+		if enabled := loaded.EnabledReporters(); len(enabled) != 1 || enabled[0] != config.SignerReporter {
+			t.Fatalf("expected only the BTC reporter to be enabled by default, got %v", enabled)
+		}
+		if err := loaded.ValidateReporters(loaded.EnabledReporters()...); err != nil {
+			t.Fatalf("invalid default %s config: %v", network, err)
+		}
+		var rewritten bytes.Buffer
+		if err := loaded.WriteYAML(&rewritten, false); err != nil {
+			t.Fatal(err)
+		}
+		if written.String() != rewritten.String() {
+			t.Fatalf("the default %s config changed on load:\n%s\n%s", network, written.String(), rewritten.String())
+		}
+
+		var shown bytes.Buffer
+		if err := loaded.WriteYAML(&shown, true); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(shown.String(), "s3cret") {
+			t.Fatalf("expected the password to be redacted:\n%s", shown.String())
+		}
+	}
+
+	if _, err := config.DefaultConfig("unknown"); err == nil {
+		t.Fatal("expected error on unknown network")
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// EnvPrefix prefixes the environment variables overriding config keys,
+	// e.g., LRZRELAYER_BTC_PASSWORD overrides btc.password
+	EnvPrefix = "LRZRELAYER"
+	// secretFileSuffix marks a key holding the path of a file whose content is the value of the key without the suffix,
+	// e.g., btc.password_file or LRZRELAYER_BTC_PASSWORD_FILE
+	secretFileSuffix = "_file"
+)
+
+var envKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// EnvName returns the environment variable overriding the given config key
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(envKeyReplacer.Replace(key))
+}
+
+// configKeys returns the keys of every field of the given struct type, descending into nested structs.
+// Maps are skipped and slices are single keys, so their entries can't be overridden from the environment.
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		key := prefix + name
+		if field.Type.Kind() == reflect.Map {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct && field.Type.NumField() > 0 {
+			keys = append(keys, configKeys(field.Type, key+".")...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// bindEnvs makes every config key overridable by its environment variable
+func bindEnvs(v *viper.Viper, keys []string) error {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(envKeyReplacer)
+	for _, key := range keys {
+		if err := v.BindEnv(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSecretFiles sets every key that has a secret file, given in the config or in the environment, to the file's content.
+// Trailing newlines are trimmed. A secret file takes precedence over the plain value of the key.
+func readSecretFiles(v *viper.Viper, keys []string) error {
+	for _, key := range keys {
+		path := os.Getenv(EnvName(key + secretFileSuffix))
+		if path == "" {
+			path = v.GetString(key + secretFileSuffix)
+		}
+		if path == "" {
+			continue
+		}
+
+		secret, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read secret file of %s: %w", key, err)
+		}
+		v.Set(key, strings.TrimRight(string(secret), "\r\n"))
+	}
+	return nil
+}
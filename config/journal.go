@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+)
+
+const (
+	defaultJournalMaxSizeMB  = 100
+	defaultJournalMaxBackups = 10
+	defaultJournalMaxAgeDays = 30
+)
+
+var defaultJournalPath = filepath.Join(defaultAppDataDir, "journal", "journal.jsonl")
+
+// JournalConfig defines the append-only journal of block events, Lorenzo queries and submission decisions
+type JournalConfig struct {
+	// Enabled turns the journal on
+	Enabled bool `mapstructure:"enabled"`
+	// Path of the active journal file; rotated files are kept next to it
+	Path string `mapstructure:"path"`
+	// MaxSizeMB is the size in megabytes at which the active journal file is rotated
+	MaxSizeMB int `mapstructure:"max-size-mb"`
+	// MaxBackups is the number of rotated journal files to keep (0 keeps all of them)
+	MaxBackups int `mapstructure:"max-backups"`
+	// MaxAgeDays is the number of days to keep rotated journal files (0 keeps them forever)
+	MaxAgeDays int `mapstructure:"max-age-days"`
+}
+
+func (cfg *JournalConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Path == "" {
+		return errors.New("path cannot be empty")
+	}
+	if cfg.MaxSizeMB <= 0 {
+		return errors.New("max-size-mb must be positive")
+	}
+	if cfg.MaxBackups < 0 {
+		return errors.New("max-backups can't be negative")
+	}
+	if cfg.MaxAgeDays < 0 {
+		return errors.New("max-age-days can't be negative")
+	}
+	return nil
+}
+
+func DefaultJournalConfig() JournalConfig {
+	return JournalConfig{
+		Enabled:    false,
+		Path:       defaultJournalPath,
+		MaxSizeMB:  defaultJournalMaxSizeMB,
+		MaxBackups: defaultJournalMaxBackups,
+		MaxAgeDays: defaultJournalMaxAgeDays,
+	}
+}
@@ -3,50 +3,190 @@ package config
 import (
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	zaplogfmt "github.com/jsternberg/zap-logfmt"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+// moduleKey is the field naming the module of a logger
+const moduleKey = "module"
+
 // NewRootLogger creates a new logger object with the given format and log level
 // (copied from https://github.com/cosmos/relayer/blob/v2.4.2/cmd/root.go#L174-L202)
 func NewRootLogger(format string, logLevel string) (*zap.Logger, error) {
+	return NewRootLoggerAt(format, zap.NewAtomicLevelAt(ParseLogLevel(logLevel)))
+}
+
+// NewRootLoggerAt creates a new logger object with the given format whose log level can be changed at runtime
+func NewRootLoggerAt(format string, level zap.AtomicLevel) (*zap.Logger, error) {
+	enc, err := newEncoder(format)
+	if err != nil {
+		return nil, err
+	}
+
+	return zap.New(zapcore.NewCore(
+		enc,
+		os.Stderr,
+		level,
+	)), nil
+}
+
+func newEncoder(format string) (zapcore.Encoder, error) {
 	config := zap.NewProductionEncoderConfig()
 	config.EncodeTime = func(ts time.Time, encoder zapcore.PrimitiveArrayEncoder) {
 		encoder.AppendString(ts.UTC().Format("2006-01-02T15:04:05.000000Z07:00"))
 	}
 	config.LevelKey = "lvl"
 
-	var enc zapcore.Encoder
 	switch format {
 	case "json":
-		enc = zapcore.NewJSONEncoder(config)
+		return zapcore.NewJSONEncoder(config), nil
 	case "auto", "console":
-		enc = zapcore.NewConsoleEncoder(config)
+		return zapcore.NewConsoleEncoder(config), nil
 	case "logfmt":
-		enc = zaplogfmt.NewEncoder(config)
+		return zaplogfmt.NewEncoder(config), nil
 	default:
 		return nil, fmt.Errorf("unrecognized log format %q", format)
 	}
+}
+
+// newLogger creates a logger writing to stderr and the log file, if any, at the given level or the level of
+// the logger's module, sampling repeated debug lines
+func (cfg *CommonConfig) newLogger(level zap.AtomicLevel) (*zap.Logger, error) {
+	enc, err := newEncoder(cfg.LogFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	sinks := []zapcore.WriteSyncer{os.Stderr}
+	if cfg.LogFile != "" {
+		sinks = append(sinks, zapcore.AddSync(&lumberjack.Logger{
+			Filename:   cfg.LogFile,
+			MaxSize:    cfg.LogFileMaxSizeMB,
+			MaxBackups: cfg.LogFileMaxBackups,
+			MaxAge:     cfg.LogFileMaxAgeDays,
+			LocalTime:  false,
+		}))
+	}
+	// levels are enforced by the module core, the inner cores take everything
+	var core zapcore.Core = zapcore.NewCore(enc, zapcore.Lock(zapcore.NewMultiWriteSyncer(sinks...)), zapcore.DebugLevel)
+	if cfg.LogSampleInterval > 0 {
+		core = &debugSampler{Core: core, state: &sampleState{interval: cfg.LogSampleInterval}}
+	}
+
+	moduleLevels := make(map[string]zapcore.Level, len(cfg.LogLevels))
+	for module, l := range cfg.LogLevels {
+		moduleLevels[module] = ParseLogLevel(l)
+	}
+	return zap.New(&moduleCore{Core: core, level: level, moduleLevels: moduleLevels}), nil
+}
+
+// moduleCore enables the entries of a logger at the level of its module, or at the root level if the module has none
+type moduleCore struct {
+	zapcore.Core
+	level        zapcore.LevelEnabler
+	moduleLevels map[string]zapcore.Level
+}
+
+func (c *moduleCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level)
+}
+
+func (c *moduleCore) With(fields []zapcore.Field) zapcore.Core {
+	level := c.level
+	for _, field := range fields {
+		if field.Key != moduleKey || field.Type != zapcore.StringType {
+			continue
+		}
+		if l, ok := c.moduleLevels[field.String]; ok {
+			level = l
+		}
+	}
+	return &moduleCore{Core: c.Core.With(fields), level: level, moduleLevels: c.moduleLevels}
+}
+
+func (c *moduleCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+func (c *moduleCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.level)
+}
+
+// debugSampler logs a debug line at most once per interval for each module and message, so that polling loops do
+// not flood the log. Other levels are always logged.
+type debugSampler struct {
+	zapcore.Core
+	module string
+	state  *sampleState // shared by the cores of all loggers
+}
+
+// sampleKey is a debug line of a module
+type sampleKey struct {
+	module  string
+	message string
+}
 
-	level := zapcore.InfoLevel
+type sampleState struct {
+	interval time.Duration
+	mu       sync.Mutex
+	since    time.Time
+	logged   map[sampleKey]struct{} // lines logged since the start of the interval
+}
+
+// sample tells whether a debug line with the given message of the given module is logged
+func (s *sampleState) sample(module string, ent zapcore.Entry) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ent.Time.Sub(s.since) >= s.interval || s.logged == nil {
+		s.since = ent.Time
+		s.logged = make(map[sampleKey]struct{})
+	}
+	key := sampleKey{module: module, message: ent.Message}
+	if _, ok := s.logged[key]; ok {
+		return false
+	}
+	s.logged[key] = struct{}{}
+	return true
+}
+
+func (c *debugSampler) With(fields []zapcore.Field) zapcore.Core {
+	module := c.module
+	for _, field := range fields {
+		if field.Key == moduleKey && field.Type == zapcore.StringType {
+			module = field.String
+		}
+	}
+	return &debugSampler{Core: c.Core.With(fields), module: module, state: c.state}
+}
+
+func (c *debugSampler) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level == zapcore.DebugLevel && !c.state.sample(c.module, ent) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// ParseLogLevel returns the level of the given log level name, info for unknown names
+func ParseLogLevel(logLevel string) zapcore.Level {
 	switch logLevel {
 	case "debug":
-		level = zapcore.DebugLevel
+		return zapcore.DebugLevel
 	case "warn":
-		level = zapcore.WarnLevel
+		return zapcore.WarnLevel
 	case "error":
-		level = zapcore.ErrorLevel
+		return zapcore.ErrorLevel
 	case "panic":
-		level = zapcore.PanicLevel
+		return zapcore.PanicLevel
 	case "fatal":
-		level = zapcore.FatalLevel
+		return zapcore.FatalLevel
 	}
-	return zap.New(zapcore.NewCore(
-		enc,
-		os.Stderr,
-		level,
-	)), nil
+	return zapcore.InfoLevel
 }
@@ -0,0 +1,60 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
+func TestLoggerModuleLevelsAndSampling(t *testing.T) {
+	cfg := config.DefaultCommonConfig()
+	cfg.LogFormat = "json"
+	cfg.LogLevel = "info"
+	cfg.LogLevels = map[string]string{"zmq": "debug", "BNB-reporter": "debug", "btcclient": "error"}
+	cfg.LogSampleInterval = time.Minute
+	cfg.LogFile = filepath.Join(t.TempDir(), "lrzrelayer.log")
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	logger, level, err := cfg.CreateReloadableLogger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zmq := logger.With(zap.String("module", "zmq"))
+	bnbreporter := logger.With(zap.String("module", "BNB-reporter"))
+	btcclient := logger.With(zap.String("module", "btcclient"))
+	reporter := logger.With(zap.String("module", "reporter"))
+	for i := 0; i < 3; i++ {
+		zmq.Debug("polling")
+		bnbreporter.Debug("polling")
+	}
+	btcclient.Warn("btcclient warning")
+	reporter.Debug("reporter debug")
+	reporter.Info("reporter info")
+	level.SetLevel(zap.DebugLevel)
+	reporter.Debug("reporter debug after reload")
+
+	written, err := os.ReadFile(cfg.LogFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(written)
+	for msg, expected := range map[string]int{
+		`"polling"`:                   2, // once per module
+		"btcclient warning":           0,
+		`"reporter debug"`:            0,
+		"reporter info":               1,
+		"reporter debug after reload": 1,
+	} {
+		if n := strings.Count(log, msg); n != expected {
+			t.Errorf("expected %s to be logged %d times, got %d:\n%s", msg, expected, n, log)
+		}
+	}
+}
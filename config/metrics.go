@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"net"
 )
@@ -16,6 +17,16 @@ type MetricsConfig struct {
 	Host string `mapstructure:"host"`
 	// Port of the prometheus server
 	ServerPort int `mapstructure:"server-port"`
+	// TLSCertFile and TLSKeyFile serve over HTTPS when both are set
+	TLSCertFile string `mapstructure:"tls-cert-file"`
+	TLSKeyFile  string `mapstructure:"tls-key-file"`
+	// Username and Password require HTTP basic auth when set
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// BearerToken requires an Authorization: Bearer header when set
+	BearerToken string `mapstructure:"bearer-token"`
+	// Pprof serves the Go profiling endpoints under /debug/pprof/
+	Pprof bool `mapstructure:"pprof"`
 }
 
 func (cfg *MetricsConfig) Validate() error {
@@ -28,9 +39,35 @@ func (cfg *MetricsConfig) Validate() error {
 		return fmt.Errorf("invalid host: %v", cfg.Host)
 	}
 
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return errors.New("tls-cert-file and tls-key-file must be set together")
+	}
+	if (cfg.Username == "") != (cfg.Password == "") {
+		return errors.New("username and password must be set together")
+	}
+	if cfg.Username != "" && cfg.BearerToken != "" {
+		return errors.New("basic auth and bearer-token cannot both be set")
+	}
+
 	return nil
 }
 
+// TLSEnabled tells whether the server serves over HTTPS
+func (cfg *MetricsConfig) TLSEnabled() bool {
+	return cfg.TLSCertFile != ""
+}
+
+// AuthEnabled tells whether the server requires basic or bearer auth
+func (cfg *MetricsConfig) AuthEnabled() bool {
+	return cfg.Username != "" || cfg.BearerToken != ""
+}
+
+// Private tells whether only authenticated or local clients can reach the server
+func (cfg *MetricsConfig) Private() bool {
+	ip := net.ParseIP(cfg.Host)
+	return cfg.AuthEnabled() || (ip != nil && ip.IsLoopback())
+}
+
 func DefaultMetricsConfig() MetricsConfig {
 	return MetricsConfig{
 		ServerPort: defaultMetricsServerPort,
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/netparams"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// NetworkConfig defines a custom Bitcoin network on top of a built-in one. Unset fields keep the value of the base network.
+type NetworkConfig struct {
+	// Base is the built-in network the custom one derives from; it defaults to signet if a signet challenge is set
+	Base string `mapstructure:"base"`
+	// GenesisHash is the hash of the genesis block
+	GenesisHash string `mapstructure:"genesis-hash"`
+	// NetMagic identifies the network on the wire; signets derive it from their challenge
+	NetMagic uint32 `mapstructure:"net-magic"`
+	// DefaultPort is the default peer-to-peer port
+	DefaultPort string `mapstructure:"default-port"`
+
+	// PowLimitBits is the highest allowed proof of work value in compact form
+	PowLimitBits uint32 `mapstructure:"pow-limit-bits"`
+	// PowNoRetargeting disables difficulty retargeting, as on regtest
+	PowNoRetargeting *bool `mapstructure:"pow-no-retargeting"`
+	// TargetTimespan is the time between difficulty retargets
+	TargetTimespan time.Duration `mapstructure:"target-timespan"`
+	// TargetTimePerBlock is the desired time to generate each block
+	TargetTimePerBlock time.Duration `mapstructure:"target-time-per-block"`
+	// RetargetAdjustmentFactor limits the difficulty adjustment of a retarget
+	RetargetAdjustmentFactor int64 `mapstructure:"retarget-adjustment-factor"`
+	// ReduceMinDifficulty allows minimum difficulty blocks after MinDiffReductionTime without a block
+	ReduceMinDifficulty  *bool         `mapstructure:"reduce-min-difficulty"`
+	MinDiffReductionTime time.Duration `mapstructure:"min-diff-reduction-time"`
+
+	// SignetChallenge is the hex-encoded block challenge script of a custom signet
+	SignetChallenge string   `mapstructure:"signet-challenge"`
+	SignetSeeds     []string `mapstructure:"signet-seeds"`
+
+	// Bech32HRPSegwit and the address IDs are the prefixes of addresses and keys
+	Bech32HRPSegwit  string `mapstructure:"bech32-hrp"`
+	PubKeyHashAddrID *uint8 `mapstructure:"pubkey-hash-addr-id"`
+	ScriptHashAddrID *uint8 `mapstructure:"script-hash-addr-id"`
+	PrivateKeyID     *uint8 `mapstructure:"private-key-id"`
+}
+
+// Params builds the parameters of the custom network with the given name
+func (cfg *NetworkConfig) Params(name string) (*chaincfg.Params, error) {
+	if name == "" {
+		return nil, errors.New("network name cannot be empty")
+	}
+	if netparams.IsBuiltin(name) {
+		return nil, fmt.Errorf("network %s is built-in and can't be redefined", name)
+	}
+
+	base := cfg.Base
+	if base == "" && cfg.SignetChallenge != "" {
+		base = types.BtcSignet.String()
+	}
+	if !netparams.IsBuiltin(base) {
+		return nil, fmt.Errorf("base has to be one of the built-in networks, got %q", base)
+	}
+	baseParams, err := netparams.GetBTCParams(base)
+	if err != nil {
+		return nil, err
+	}
+	params := *baseParams
+
+	if cfg.SignetChallenge != "" {
+		if base != types.BtcSignet.String() {
+			return nil, errors.New("signet-challenge requires signet as base")
+		}
+		challenge, err := hex.DecodeString(cfg.SignetChallenge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signet-challenge: %w", err)
+		}
+		seeds := make([]chaincfg.DNSSeed, 0, len(cfg.SignetSeeds))
+		for _, seed := range cfg.SignetSeeds {
+			seeds = append(seeds, chaincfg.DNSSeed{Host: seed})
+		}
+		params = chaincfg.CustomSignetParams(challenge, seeds)
+	}
+	params.Name = name
+
+	if cfg.GenesisHash != "" {
+		hash, err := chainhash.NewHashFromStr(cfg.GenesisHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid genesis-hash: %w", err)
+		}
+		if !hash.IsEqual(params.GenesisHash) {
+			// the relayer only needs the hash, the genesis block of the base network would be inconsistent with it
+			params.GenesisHash = hash
+			params.GenesisBlock = nil
+		}
+	}
+	if cfg.NetMagic != 0 {
+		params.Net = wire.BitcoinNet(cfg.NetMagic)
+	}
+	if cfg.DefaultPort != "" {
+		params.DefaultPort = cfg.DefaultPort
+	}
+
+	if cfg.PowLimitBits != 0 {
+		params.PowLimitBits = cfg.PowLimitBits
+		params.PowLimit = blockchain.CompactToBig(cfg.PowLimitBits)
+		if params.PowLimit.Sign() <= 0 {
+			return nil, fmt.Errorf("pow-limit-bits %#x is not a positive target", cfg.PowLimitBits)
+		}
+	}
+	if cfg.PowNoRetargeting != nil {
+		params.PoWNoRetargeting = *cfg.PowNoRetargeting
+	}
+	if cfg.TargetTimespan != 0 {
+		params.TargetTimespan = cfg.TargetTimespan
+	}
+	if cfg.TargetTimePerBlock != 0 {
+		params.TargetTimePerBlock = cfg.TargetTimePerBlock
+	}
+	if cfg.RetargetAdjustmentFactor != 0 {
+		params.RetargetAdjustmentFactor = cfg.RetargetAdjustmentFactor
+	}
+	if cfg.ReduceMinDifficulty != nil {
+		params.ReduceMinDifficulty = *cfg.ReduceMinDifficulty
+	}
+	if cfg.MinDiffReductionTime != 0 {
+		params.MinDiffReductionTime = cfg.MinDiffReductionTime
+	}
+	if params.TargetTimespan < 0 || params.TargetTimePerBlock <= 0 || params.TargetTimespan < params.TargetTimePerBlock {
+		return nil, errors.New("target-timespan has to be at least target-time-per-block, which has to be positive")
+	}
+	if params.RetargetAdjustmentFactor <= 0 {
+		return nil, errors.New("retarget-adjustment-factor has to be positive")
+	}
+	if params.ReduceMinDifficulty && params.MinDiffReductionTime <= 0 {
+		return nil, errors.New("min-diff-reduction-time has to be positive when reduce-min-difficulty is set")
+	}
+
+	if cfg.Bech32HRPSegwit != "" {
+		params.Bech32HRPSegwit = cfg.Bech32HRPSegwit
+	}
+	if cfg.PubKeyHashAddrID != nil {
+		params.PubKeyHashAddrID = *cfg.PubKeyHashAddrID
+	}
+	if cfg.ScriptHashAddrID != nil {
+		params.ScriptHashAddrID = *cfg.ScriptHashAddrID
+	}
+	if cfg.PrivateKeyID != nil {
+		params.PrivateKeyID = *cfg.PrivateKeyID
+	}
+	if params.PubKeyHashAddrID == params.ScriptHashAddrID {
+		return nil, errors.New("pubkey-hash-addr-id and script-hash-addr-id must differ")
+	}
+
+	return &params, nil
+}
+
+// validateNetworks checks that every custom network builds valid parameters
+func validateNetworks(networks map[string]NetworkConfig) error {
+	for name, network := range networks {
+		if _, err := network.Params(name); err != nil {
+			return fmt.Errorf("network %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ResolveNetworks makes the custom networks available to BTCConfig.NetParams and ReporterConfig.NetParams
+func (cfg *Config) ResolveNetworks() error {
+	custom := make(map[string]*chaincfg.Params, len(cfg.Networks))
+	for name, network := range cfg.Networks {
+		params, err := network.Params(name)
+		if err != nil {
+			return fmt.Errorf("network %s: %w", name, err)
+		}
+		custom[name] = params
+	}
+	registry, err := netparams.NewRegistry(custom)
+	if err != nil {
+		return err
+	}
+	cfg.BTC.Networks = registry
+	cfg.Reporter.Networks = registry
+	return nil
+}
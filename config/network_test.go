@@ -0,0 +1,101 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/netparams"
+)
+
+func TestNetworkConfig(t *testing.T) {
+	network := config.NetworkConfig{
+		SignetChallenge: "51", // OP_TRUE
+		Bech32HRPSegwit: "sb",
+	}
+	params, err := network.Params("mysignet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Name != "mysignet" || params.Net == chaincfg.SigNetParams.Net || params.Bech32HRPSegwit != "sb" {
+		t.Fatalf("unexpected custom signet params: %s %v %s", params.Name, params.Net, params.Bech32HRPSegwit)
+	}
+	if !params.GenesisHash.IsEqual(chaincfg.SigNetParams.GenesisHash) {
+		t.Fatal("expected the custom signet to keep the signet genesis")
+	}
+
+	if _, err := network.Params("signet"); err == nil {
+		t.Fatal("expected error on redefining a built-in network")
+	}
+	if _, err := (&config.NetworkConfig{Base: "mainnet", SignetChallenge: "51"}).Params("bad"); err == nil {
+		t.Fatal("expected error on a signet challenge without signet base")
+	}
+	if _, err := (&config.NetworkConfig{Base: "regtest", GenesisHash: "nothex"}).Params("bad"); err == nil {
+		t.Fatal("expected error on malformed genesis hash")
+	}
+
+	cfg := config.Config{
+		Networks: map[string]config.NetworkConfig{"mysignet": network},
+		Reporter: config.ReporterConfig{NetParams: "mysignet", BTCCacheSize: 1000, MaxHeadersInMsg: 100},
+	}
+	if err := cfg.Reporter.Validate(); err == nil {
+		t.Fatal("expected a custom network to be unknown before the networks are resolved")
+	}
+	if err := cfg.ResolveNetworks(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Reporter.Validate(); err != nil {
+		t.Fatalf("expected the custom network to be valid net params, got %v", err)
+	}
+	if params, err := cfg.BTC.Networks.GetBTCParams("mysignet"); err != nil || params.Bech32HRPSegwit != "sb" {
+		t.Fatalf("expected the BTC section to resolve the custom network, got %v", err)
+	}
+	// the custom network is not visible to other configs
+	if _, err := netparams.GetBTCParams("mysignet"); err == nil {
+		t.Fatal("expected custom networks to stay out of the built-in networks")
+	}
+
+	testnet4, err := netparams.GetBTCParams("testnet4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if testnet4.GenesisHash.String() != "00000000da84f2bafbbc53dee25a72ae507ff4914b867c565be350b0da8bf043" {
+		t.Fatalf("unexpected testnet4 genesis %s", testnet4.GenesisHash)
+	}
+}
+
+func TestResolveNetworksFromFile(t *testing.T) {
+	overlay := filepath.Join(t.TempDir(), "overlay.yml")
+	networks := "networks:\n  devnet:\n    base: regtest\n    default-port: \"18555\"\n    bech32-hrp: dev\n" +
+		"btc:\n  net-params: devnet\nreporter:\n  netparams: devnet\n"
+	if err := os.WriteFile(overlay, []byte(networks), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.New(filepath.Join("..", "sample-lrzrelayer.yml"), overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params, err := cfg.Reporter.Networks.GetBTCParams(cfg.Reporter.NetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Name != "devnet" || params.DefaultPort != "18555" || params.Bech32HRPSegwit != "dev" {
+		t.Fatalf("expected the set fields to override the base network, got %s %s %s", params.Name, params.DefaultPort, params.Bech32HRPSegwit)
+	}
+	base := chaincfg.RegressionNetParams
+	if !params.GenesisHash.IsEqual(base.GenesisHash) || params.Net != base.Net || params.PowLimit.Cmp(base.PowLimit) != 0 ||
+		params.PoWNoRetargeting != base.PoWNoRetargeting || params.PubKeyHashAddrID != base.PubKeyHashAddrID {
+		t.Fatal("expected the unset fields to keep the value of the base network")
+	}
+	if btcParams, err := cfg.BTC.Networks.GetBTCParams(cfg.BTC.NetParams); err != nil || btcParams != params {
+		t.Fatalf("expected the BTC section to resolve the same network, got %v", err)
+	}
+	// the base network itself is left untouched
+	if chaincfg.RegressionNetParams.Name != "regtest" || chaincfg.RegressionNetParams.DefaultPort != "18444" {
+		t.Fatal("expected resolving a custom network to leave its base unchanged")
+	}
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ReloadableKeys are the config keys whose changes are applied at runtime when the config is reloaded.
+// Changing any other key, e.g., an endpoint or a key, needs a restart. This includes the address and TLS files
+// of the metrics server, which is listening already, and the webhook of the alerts, whose client is created once.
+var ReloadableKeys = map[string]bool{
+	"common.log-level":               true,
+	"reporter.delay_blocks":          true,
+	"reporter.max_headers_in_msg":    true,
+	"reporter.submit_batch_window":   true,
+	"reporter.stall_after":           true,
+	"bnbreporter.delay_blocks":       true,
+	"bnbreporter.poll_interval":      true,
+	"bnbreporter.stall_after":        true,
+	"signers.balance-check-interval": true,
+	"signers.warning-balance":        true,
+	"signers.critical-balance":       true,
+	"metrics.username":               true,
+	"metrics.password":               true,
+	"metrics.bearer-token":           true,
+	"metrics.pprof":                  true,
+	"alerts.dedup-window":            true,
+	"alerts.max-per-hour":            true,
+	"alerts.btc-stall-after":         true,
+	"alerts.bnb-stall-after":         true,
+	"alerts.reorg-depth":             true,
+	"alerts.bootstrap-failures":      true,
+}
+
+// Change is a config key whose value differs between two configs
+type Change struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Key, c.Old, c.New)
+}
+
+// Diff returns the keys whose values differ between the current and the next config, in the order of the
+// config struct. Values of secrets are masked.
+func Diff(current, next *Config) []Change {
+	var changes []Change
+	diffValues(reflect.ValueOf(*current), reflect.ValueOf(*next), "", &changes)
+	return changes
+}
+
+func diffValues(current, next reflect.Value, prefix string, changes *[]Change) {
+	for i := 0; i < current.NumField(); i++ {
+		field := current.Type().Field(i)
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		key := prefix + name
+		o, n := current.Field(i), next.Field(i)
+		if field.Type.Kind() == reflect.Struct && field.Type.NumField() > 0 {
+			diffValues(o, n, key+".", changes)
+			continue
+		}
+		if reflect.DeepEqual(o.Interface(), n.Interface()) {
+			continue
+		}
+
+		change := Change{Key: key, Old: fmt.Sprintf("%v", o.Interface()), New: fmt.Sprintf("%v", n.Interface())}
+		if isSecretKey(key) {
+			change.Old, change.New = redacted, redacted
+		}
+		*changes = append(*changes, change)
+	}
+}
+
+// CheckReload returns an error listing the changes that can't be applied at runtime
+func CheckReload(changes []Change) error {
+	var rejected []string
+	for _, change := range changes {
+		if !ReloadableKeys[change.Key] {
+			rejected = append(rejected, change.String())
+		}
+	}
+	if len(rejected) > 0 {
+		return fmt.Errorf("changes that need a restart: %s", strings.Join(rejected, "; "))
+	}
+	return nil
+}
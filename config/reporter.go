@@ -2,32 +2,76 @@ package config
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/netparams"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
 const (
 	minBTCCacheSize = 1000
 	maxHeadersInMsg = 100 // maximum number of headers in a MsgInsertHeaders message
+
+	defaultDelayBlocks       = 3
+	defaultSubmitBatchWindow = 10 * time.Second
+	defaultStallAfter        = 30 * time.Minute
 )
 
 // ReporterConfig defines configuration for the reporter.
 type ReporterConfig struct {
-	NetParams       string `mapstructure:"netparams"`          // should be mainnet|testnet|simnet|signet
-	BTCCacheSize    uint64 `mapstructure:"btc_cache_size"`     // size of the BTC cache
+	Enabled         bool   `mapstructure:"enabled"`            // whether `lrzrelayer start` runs the reporter
+	NetParams       string `mapstructure:"netparams"`          // should be mainnet|testnet|testnet4|simnet|regtest|signet or a custom network
+	BTCCacheSize    uint64 `mapstructure:"btc_cache_size"`     // size of the BTC cache, 0 makes it unbounded
 	MaxHeadersInMsg uint32 `mapstructure:"max_headers_in_msg"` // maximum number of headers in a MsgInsertHeaders message
 	DelayBlocks     uint64 `mapstructure:"delay_blocks"`       // number of blocks to wait before inserting headers
+	// SubmitBatchWindow is how long headers from consecutive block events are held back to be submitted
+	// together, up to MaxHeadersInMsg headers; 0 submits the headers of every event right away
+	SubmitBatchWindow time.Duration `mapstructure:"submit_batch_window"`
+	// StallAfter is how long the reporter may relay nothing while the BTC tip is ahead before the watchdog
+	// re-subscribes to BTC blocks and bootstraps again; 0 disables the watchdog
+	StallAfter time.Duration `mapstructure:"stall_after"`
+	// Checkpoints are trusted BTC block hashes; the reporter refuses to bootstrap or relay if the BTC node disagrees
+	Checkpoints []CheckpointConfig `mapstructure:"checkpoints"`
+	// Networks resolves NetParams, set from the networks section by New; nil knows the built-in networks only
+	Networks *netparams.Registry `mapstructure:"-"`
 }
 
 func (cfg *ReporterConfig) Validate() error {
-	if _, ok := types.GetValidNetParams()[cfg.NetParams]; !ok {
+	if !cfg.Networks.Exists(cfg.NetParams) {
 		return fmt.Errorf("invalid net params")
 	}
-	if cfg.BTCCacheSize < minBTCCacheSize {
-		return fmt.Errorf("BTC cache size has to be at least %d", minBTCCacheSize)
+	if cfg.BTCCacheSize != 0 && cfg.BTCCacheSize < minBTCCacheSize {
+		return fmt.Errorf("BTC cache size has to be 0 (unbounded) or at least %d", minBTCCacheSize)
 	}
 	if cfg.MaxHeadersInMsg < maxHeadersInMsg {
 		return fmt.Errorf("max_headers_in_msg has to be at least %d", maxHeadersInMsg)
 	}
+	if cfg.SubmitBatchWindow < 0 {
+		return fmt.Errorf("submit_batch_window can't be negative")
+	}
+	if cfg.StallAfter < 0 {
+		return fmt.Errorf("stall_after can't be negative")
+	}
+	if err := validateCheckpoints(cfg.Checkpoints, func(hash string) error {
+		_, err := chainhash.NewHashFromStr(hash)
+		return err
+	}); err != nil {
+		return err
+	}
 	return nil
 }
+
+func DefaultReporterConfig() ReporterConfig {
+	return ReporterConfig{
+		Enabled:           true,
+		NetParams:         types.BtcSimnet.String(),
+		BTCCacheSize:      minBTCCacheSize,
+		MaxHeadersInMsg:   maxHeadersInMsg,
+		DelayBlocks:       defaultDelayBlocks,
+		SubmitBatchWindow: defaultSubmitBatchWindow,
+		StallAfter:        defaultStallAfter,
+		Checkpoints:       []CheckpointConfig{},
+	}
+}
@@ -1,3 +1,43 @@
 package config_test
 
+import (
+	"testing"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
 // TODO: tests on reporter config
+
+func TestReporterConfigCheckpoints(t *testing.T) {
+	validCfg := func() config.ReporterConfig {
+		return config.ReporterConfig{
+			NetParams:       "testnet",
+			BTCCacheSize:    1000,
+			MaxHeadersInMsg: 100,
+			DelayBlocks:     3,
+		}
+	}
+
+	cfg := validCfg()
+	cfg.Checkpoints = []config.CheckpointConfig{
+		{Height: 0, Hash: "000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid checkpoints, got %v", err)
+	}
+
+	cfg = validCfg()
+	cfg.Checkpoints = []config.CheckpointConfig{{Height: 1, Hash: "not-a-hash"}}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error on malformed checkpoint hash")
+	}
+
+	cfg = validCfg()
+	cfg.Checkpoints = []config.CheckpointConfig{
+		{Height: 0, Hash: "000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943"},
+		{Height: 0, Hash: "000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943"},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error on duplicate checkpoint height")
+	}
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// SignerAssignmentPerReporter signs the txs of each reporter with the keys listed for it
+	SignerAssignmentPerReporter = "per-reporter"
+	// SignerAssignmentRoundRobin deals lorenzo.key and the keys out to the reporters in turn, and each reporter
+	// rotates its txs over its share
+	SignerAssignmentRoundRobin = "round-robin"
+
+	defaultSequenceRetries = 3
+)
+
+// Reporters that sign Lorenzo txs
+const (
+	SignerReporter    = "reporter"
+	SignerBNBReporter = "bnbreporter"
+)
+
+// SignersConfig defines the Lorenzo keys the reporters sign their txs with
+type SignersConfig struct {
+	// Keys are the names of keyring keys usable in addition to lorenzo.key
+	Keys []string `mapstructure:"keys"`
+	// Assignment is how keys are assigned to reporters (per-reporter|round-robin), per-reporter if empty
+	Assignment string `mapstructure:"assignment"`
+	// Reporters lists the keys of each reporter (reporter|bnbreporter) under per-reporter assignment.
+	// A reporter without keys signs with lorenzo.key
+	Reporters map[string][]string `mapstructure:"reporters"`
+	// SequenceRetries is how many times a tx is resent after an account sequence mismatch, 3 if 0
+	SequenceRetries uint `mapstructure:"sequence-retries"`
+	// BalanceCheckInterval is how often the balance of every key is checked, 0 disables the check.
+	// It has to be positive if a balance threshold is set
+	BalanceCheckInterval time.Duration `mapstructure:"balance-check-interval"`
+	// WarningBalance is the balance (e.g. 10000000ulrz) below which a key is reported as running low
+	WarningBalance string `mapstructure:"warning-balance"`
+	// CriticalBalance is the balance below which a key stops broadcasting txs until it is funded again
+	CriticalBalance string `mapstructure:"critical-balance"`
+}
+
+// Thresholds parses the warning and critical balances. Either is nil if unset.
+func (cfg *SignersConfig) Thresholds() (warning *sdk.Coin, critical *sdk.Coin, err error) {
+	if cfg.WarningBalance != "" {
+		coin, err := sdk.ParseCoinNormalized(cfg.WarningBalance)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid warning-balance: %w", err)
+		}
+		warning = &coin
+	}
+	if cfg.CriticalBalance != "" {
+		coin, err := sdk.ParseCoinNormalized(cfg.CriticalBalance)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid critical-balance: %w", err)
+		}
+		critical = &coin
+	}
+	if warning != nil && critical != nil {
+		if warning.Denom != critical.Denom {
+			return nil, nil, fmt.Errorf("warning-balance and critical-balance must have the same denom")
+		}
+		if warning.IsLT(*critical) {
+			return nil, nil, fmt.Errorf("warning-balance can't be lower than critical-balance")
+		}
+	}
+	return warning, critical, nil
+}
+
+func (cfg *SignersConfig) Validate() error {
+	switch cfg.Assignment {
+	case "", SignerAssignmentPerReporter, SignerAssignmentRoundRobin:
+	default:
+		return fmt.Errorf("invalid assignment %q, should be %s|%s", cfg.Assignment, SignerAssignmentPerReporter, SignerAssignmentRoundRobin)
+	}
+
+	known := make(map[string]bool, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		if key == "" {
+			return fmt.Errorf("key names cannot be empty")
+		}
+		if known[key] {
+			return fmt.Errorf("duplicate key %s", key)
+		}
+		known[key] = true
+	}
+
+	used := make(map[string]string)
+	for reporter, keys := range cfg.Reporters {
+		if reporter != SignerReporter && reporter != SignerBNBReporter {
+			return fmt.Errorf("unknown reporter %s, should be %s|%s", reporter, SignerReporter, SignerBNBReporter)
+		}
+		for _, key := range keys {
+			if !known[key] {
+				return fmt.Errorf("key %s of %s is not listed in keys", key, reporter)
+			}
+			// sharing a key between reporters is what causes sequence mismatches in the first place
+			if other, ok := used[key]; ok && other != reporter {
+				return fmt.Errorf("key %s is assigned to both %s and %s", key, other, reporter)
+			}
+			used[key] = reporter
+		}
+	}
+
+	if cfg.BalanceCheckInterval < 0 {
+		return fmt.Errorf("balance-check-interval can't be negative")
+	}
+	warning, critical, err := cfg.Thresholds()
+	if err != nil {
+		return err
+	}
+	// without the check, the keys would never be found low whatever the thresholds
+	if (warning != nil || critical != nil) && cfg.BalanceCheckInterval == 0 {
+		return fmt.Errorf("warning-balance and critical-balance require a positive balance-check-interval")
+	}
+	return nil
+}
+
+// signerReporters are the reporters that keys are dealt out to under round-robin assignment, in turn
+var signerReporters = []string{SignerReporter, SignerBNBReporter}
+
+// KeysOf returns the keys the given reporter signs with, given the default key lorenzo.key
+func (cfg *SignersConfig) KeysOf(reporter string, defaultKey string) []string {
+	if cfg.Assignment == SignerAssignmentRoundRobin {
+		all := []string{defaultKey}
+		for _, key := range cfg.Keys {
+			if key != defaultKey {
+				all = append(all, key)
+			}
+		}
+		turn := 0
+		for i, r := range signerReporters {
+			if r == reporter {
+				turn = i
+			}
+		}
+		var keys []string
+		for i := turn; i < len(all); i += len(signerReporters) {
+			keys = append(keys, all[i])
+		}
+		if len(keys) == 0 {
+			// fewer keys than reporters, the key is shared
+			keys = []string{all[turn%len(all)]}
+		}
+		return keys
+	}
+	if keys := cfg.Reporters[reporter]; len(keys) > 0 {
+		return keys
+	}
+	return []string{defaultKey}
+}
+
+func DefaultSignersConfig() SignersConfig {
+	return SignersConfig{
+		Keys:            []string{},
+		Assignment:      SignerAssignmentPerReporter,
+		Reporters:       map[string][]string{},
+		SequenceRetries: defaultSequenceRetries,
+	}
+}
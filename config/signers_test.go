@@ -0,0 +1,63 @@
+package config_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
+func TestSignersConfig(t *testing.T) {
+	cfg := config.SignersConfig{
+		Keys:       []string{"key1", "key2"},
+		Assignment: config.SignerAssignmentPerReporter,
+		Reporters:  map[string][]string{config.SignerBNBReporter: {"key2"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+	if keys := cfg.KeysOf(config.SignerReporter, "node0"); !reflect.DeepEqual(keys, []string{"node0"}) {
+		t.Errorf("reporter without keys should sign with the default key, got %v", keys)
+	}
+	if keys := cfg.KeysOf(config.SignerBNBReporter, "node0"); !reflect.DeepEqual(keys, []string{"key2"}) {
+		t.Errorf("unexpected bnbreporter keys %v", keys)
+	}
+
+	cfg.Assignment = config.SignerAssignmentRoundRobin
+	if keys := cfg.KeysOf(config.SignerReporter, "node0"); !reflect.DeepEqual(keys, []string{"node0", "key2"}) {
+		t.Errorf("unexpected round-robin keys %v", keys)
+	}
+	if keys := cfg.KeysOf(config.SignerBNBReporter, "node0"); !reflect.DeepEqual(keys, []string{"key1"}) {
+		t.Errorf("expected round-robin to give the bnbreporter the other keys, got %v", keys)
+	}
+	single := config.SignersConfig{Assignment: config.SignerAssignmentRoundRobin}
+	if keys := single.KeysOf(config.SignerBNBReporter, "node0"); !reflect.DeepEqual(keys, []string{"node0"}) {
+		t.Errorf("expected a single key to be shared, got %v", keys)
+	}
+
+	cfg.Assignment = config.SignerAssignmentPerReporter
+	cfg.Reporters[config.SignerReporter] = []string{"key2"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected a key shared by two reporters to be rejected")
+	}
+
+	cfg.Reporters = map[string][]string{config.SignerReporter: {"key3"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an unlisted key to be rejected")
+	}
+
+	cfg.Reporters = nil
+	cfg.WarningBalance, cfg.CriticalBalance = "10ulrz", "100ulrz"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected a warning balance below the critical balance to be rejected")
+	}
+	cfg.WarningBalance = "1000ulrz"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected balance thresholds without a balance check interval to be rejected")
+	}
+	cfg.BalanceCheckInterval = time.Minute
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid balance thresholds rejected: %v", err)
+	}
+}
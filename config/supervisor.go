@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+const (
+	defaultSupervisorInitialBackoff = 5 * time.Second
+	defaultSupervisorMaxBackoff     = 5 * time.Minute
+	defaultSupervisorJitter         = 0.2
+	defaultSupervisorMaxRestarts    = 10
+	defaultSupervisorRestartWindow  = 10 * time.Minute
+)
+
+// SupervisorConfig defines how `lrzrelayer start` restarts failed reporters
+type SupervisorConfig struct {
+	// InitialBackoff is the delay before the first restart of a failed reporter
+	InitialBackoff time.Duration `mapstructure:"initial-backoff"`
+	// MaxBackoff caps the delay between restarts, which doubles after every consecutive failure
+	MaxBackoff time.Duration `mapstructure:"max-backoff"`
+	// Jitter is the fraction in [0, 1) by which each delay is randomly shortened or lengthened
+	Jitter float64 `mapstructure:"jitter"`
+	// MaxRestarts is the number of consecutive restarts after which the process exits (0 restarts forever)
+	MaxRestarts int `mapstructure:"max-restarts"`
+	// RestartWindow is how long a reporter must run for its failure not to count as consecutive
+	RestartWindow time.Duration `mapstructure:"restart-window"`
+	// ExitOn are the error classes on which the process exits instead of restarting the reporter
+	ExitOn []string `mapstructure:"exit-on"`
+}
+
+func (cfg *SupervisorConfig) Validate() error {
+	if cfg.InitialBackoff <= 0 {
+		return errors.New("initial-backoff must be positive")
+	}
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		return errors.New("max-backoff can't be less than initial-backoff")
+	}
+	if cfg.Jitter < 0 || cfg.Jitter >= 1 {
+		return errors.New("jitter must be in [0, 1)")
+	}
+	if cfg.MaxRestarts < 0 {
+		return errors.New("max-restarts can't be negative")
+	}
+	if cfg.RestartWindow < 0 {
+		return errors.New("restart-window can't be negative")
+	}
+	for _, class := range cfg.ExitOn {
+		if !types.IsClass(class) {
+			return fmt.Errorf("unknown error class %q in exit-on", class)
+		}
+	}
+	return nil
+}
+
+func DefaultSupervisorConfig() SupervisorConfig {
+	return SupervisorConfig{
+		InitialBackoff: defaultSupervisorInitialBackoff,
+		MaxBackoff:     defaultSupervisorMaxBackoff,
+		Jitter:         defaultSupervisorJitter,
+		MaxRestarts:    defaultSupervisorMaxRestarts,
+		RestartWindow:  defaultSupervisorRestartWindow,
+		ExitOn:         []string{types.ClassConfig},
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"errors"
+)
+
+const (
+	defaultTracingEndpoint    = "localhost:4318"
+	defaultTracingSampleRatio = 1.0
+)
+
+// TracingConfig defines the export of the reporters' spans to an OTLP collector
+type TracingConfig struct {
+	// Enabled turns the export of spans on
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoint is the host:port of the collector's OTLP/HTTP receiver
+	Endpoint string `mapstructure:"endpoint"`
+	// Insecure exports spans over plain HTTP instead of HTTPS
+	Insecure bool `mapstructure:"insecure"`
+	// SampleRatio is the fraction in [0, 1] of traces that are exported
+	SampleRatio float64 `mapstructure:"sample-ratio"`
+}
+
+func (cfg *TracingConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Endpoint == "" {
+		return errors.New("endpoint cannot be empty")
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return errors.New("sample-ratio must be in [0, 1]")
+	}
+	return nil
+}
+
+func DefaultTracingConfig() TracingConfig {
+	return TracingConfig{
+		Enabled:     false,
+		Endpoint:    defaultTracingEndpoint,
+		Insecure:    true,
+		SampleRatio: defaultTracingSampleRatio,
+	}
+}
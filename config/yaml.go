@@ -0,0 +1,175 @@
+package config
+
+import (
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces the values of secrets in printed configs
+const redacted = "***"
+
+// keyComments are written next to the keys of printed configs
+var keyComments = map[string]string{
+	"common.log-format":              "format of the log (json|auto|console|logfmt)",
+	"common.log-level":               "log level (debug|info|warn|error|panic|fatal)",
+	"common.log-levels":              "levels of modules overriding log-level, e.g., zmq: info",
+	"common.log-file":                "also write the log to this file, rotated like the journal; stderr only if empty",
+	"common.log-sample-interval":     "a debug line of a module with the same message is logged at most once per this interval, 0s logs all",
+	"btc.no-client-tls":              "use true for bitcoind as it does not support tls",
+	"btc.ca-file":                    "only need in {btcd}",
+	"btc.net-params":                 "mainnet|testnet|testnet4|simnet|regtest|signet or a network defined under networks",
+	"btc.password":                   "or password_file: /path/to/secret",
+	"btc.btc-backend":                "{btcd, bitcoind}",
+	"btc.rpc-timeout":                "deadline of every call to the BTC node, 0s waits until shutdown",
+	"btc.zmq-seq-endpoint":           "if btc-backend is bitcoind",
+	"metrics.tls-cert-file":          "serve over HTTPS with this certificate and tls-key-file",
+	"metrics.username":               "require basic auth with this username and password, or password_file: /path/to/secret",
+	"metrics.bearer-token":           "require this bearer token instead, or bearer-token_file: /path/to/secret",
+	"metrics.pprof":                  "serve the Go profiling endpoints under /debug/pprof/",
+	"lorenzo.chain-id":               "chain id of the Lorenzo network",
+	"reporter.enabled":               "run the BTC reporter in `lrzrelayer start`",
+	"reporter.btc_cache_size":        "0 keeps every block fetched during bootstrap and Lorenzo outages",
+	"reporter.submit_batch_window":   "batch headers of consecutive block events into one tx, 0s submits every event right away",
+	"reporter.stall_after":           "re-subscribe and bootstrap after relaying nothing for this long while behind the BTC tip, 0s disables",
+	"reporter.checkpoints":           "trusted BTC block hashes, the reporter refuses to run if the BTC node disagrees",
+	"bnbreporter.enabled":            "run the BNB reporter in `lrzrelayer start`, set base_height first",
+	"bnbreporter.base_height":        "height of the base header of Lorenzo's BNB light client",
+	"bnbreporter.poll_interval":      "how often the BNB tip is polled while waiting for new blocks",
+	"bnbreporter.rpc_timeout":        "deadline of every call to the BNB node, 0s waits until shutdown",
+	"bnbreporter.stall_after":        "reconnect and bootstrap after relaying nothing for this long, 0s disables",
+	"bnbreporter.checkpoints":        "trusted BNB block hashes, the reporter refuses to run if the BNB node disagrees",
+	"journal.enabled":                "record block events, tip queries and submissions for `lrzrelayer replay`",
+	"journal.max-size-mb":            "rotate the journal file once it reaches this size",
+	"journal.max-backups":            "number of rotated journal files to keep (0 keeps all)",
+	"journal.max-age-days":           "days to keep rotated journal files (0 keeps them forever)",
+	"audit.enabled":                  "record every header tx sent to Lorenzo to a hash-chained log, checked by `lrzrelayer audit verify`",
+	"audit.path":                     "never rotated; processes running at the same time need different paths",
+	"signers.keys":                   "keyring keys usable in addition to lorenzo.key",
+	"signers.assignment":             "per-reporter|round-robin, which deals the keys out to the reporters in turn; reporters sharing a key hit sequence mismatches",
+	"signers.reporters":              "keys of each reporter under per-reporter assignment, lorenzo.key if unset",
+	"signers.sequence-retries":       "times a tx is resent after an account sequence mismatch",
+	"signers.balance-check-interval": "how often the balance of every key is checked, 0s disables the check and requires unset balances",
+	"signers.warning-balance":        "a warning is logged when a key's balance falls below this, e.g., 10000000ulrz",
+	"signers.critical-balance":       "keys below this stop broadcasting; the reporters pause when all keys are below it",
+	"supervisor.initial-backoff":     "delay before the first restart of a failed reporter in `lrzrelayer start`",
+	"supervisor.max-backoff":         "the delay doubles after every consecutive failure up to this",
+	"supervisor.jitter":              "fraction by which each delay is randomly shortened or lengthened",
+	"supervisor.max-restarts":        "consecutive restarts of a reporter after which the process exits (0 restarts forever)",
+	"supervisor.restart-window":      "a reporter running this long resets its consecutive failures",
+	"supervisor.exit-on":             "error classes exiting the process right away (config|source-chain|lorenzo|inconsistency|insufficient-funds|unknown)",
+	"alerts.enabled":                 "notify stalls, deep reorgs, consistency failures, low balances and repeated bootstrap failures",
+	"alerts.webhook-url":             "receives every notification as a JSON POST, e.g., a chat or paging webhook",
+	"alerts.timeout":                 "deadline of every POST to the webhook",
+	"alerts.dedup-window":            "a notification of the same condition is not repeated for this long",
+	"alerts.max-per-hour":            "notifications beyond this in any hour are dropped",
+	"alerts.btc-stall-after":         "the BTC reporter is stalled after relaying nothing for this long",
+	"alerts.bnb-stall-after":         "the BNB reporter is stalled after relaying nothing for this long",
+	"alerts.reorg-depth":             "BTC reorgs replacing at least this many blocks are notified",
+	"alerts.bootstrap-failures":      "consecutive failed bootstraps of a reporter that are notified",
+	"tracing.enabled":                "export spans of the reporter stages to an OTLP collector",
+	"tracing.endpoint":               "host:port of the collector's OTLP/HTTP receiver",
+	"tracing.insecure":               "export over plain HTTP instead of HTTPS",
+	"tracing.sample-ratio":           "fraction of traces exported, in [0, 1]",
+	"networks":                       "custom Bitcoin networks usable as net-params/netparams, unset fields keep the base network's value",
+}
+
+// isSecretKey tells whether the value of the given config key is a secret. Webhook URLs usually embed a token.
+func isSecretKey(key string) bool {
+	return strings.HasSuffix(key, "password") || strings.HasSuffix(key, "token") || strings.HasSuffix(key, "webhook-url")
+}
+
+// WriteYAML writes the config in the format of the config file, commented like sample-lrzrelayer.yml.
+// With redactSecrets, the values of secrets are replaced by ***.
+func (cfg *Config) WriteYAML(w io.Writer, redactSecrets bool) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(reflect.ValueOf(*cfg), "", redactSecrets)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func yamlNode(v reflect.Value, key string, redactSecrets bool) *yaml.Node {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: d.String()}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+			if name == "" || name == "-" || !field.IsExported() {
+				continue
+			}
+			node.Content = append(node.Content, yamlField(name, prefixed(key, name), v.Field(i), redactSecrets)...)
+		}
+		return node
+	case reflect.Map:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		if v.Len() == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		names := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			names = append(names, k.String())
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			value := yamlNode(v.MapIndex(reflect.ValueOf(name)), prefixed(key, name), redactSecrets)
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+		}
+		return node
+	case reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		if v.Len() == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		for i := 0; i < v.Len(); i++ {
+			node.Content = append(node.Content, yamlNode(v.Index(i), key, redactSecrets))
+		}
+		return node
+	case reflect.Pointer:
+		if v.IsNil() {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "~"}
+		}
+		return yamlNode(v.Elem(), key, redactSecrets)
+	}
+
+	if redactSecrets && isSecretKey(key) && v.Kind() == reflect.String && v.Len() > 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: redacted}
+	}
+	node := &yaml.Node{}
+	if err := node.Encode(v.Interface()); err != nil {
+		// only reached for kinds the config does not use, e.g., channels
+		panic(err)
+	}
+	return node
+}
+
+// yamlField returns the key and value nodes of a struct field, with the comment of the key
+func yamlField(name, key string, v reflect.Value, redactSecrets bool) []*yaml.Node {
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: name}
+	valueNode := yamlNode(v, key, redactSecrets)
+	if comment, ok := keyComments[key]; ok {
+		if valueNode.Kind == yaml.ScalarNode || valueNode.Style == yaml.FlowStyle {
+			valueNode.LineComment = comment
+		} else {
+			keyNode.LineComment = comment
+		}
+	}
+	return []*yaml.Node{keyNode, valueNode}
+}
+
+func prefixed(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,113 @@
+// Package journal records what the reporters observe and decide into an append-only, rotating
+// JSON lines log, so that production incidents can be replayed locally.
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	btclctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// Journal appends records to a rotating on-disk log. It is safe for concurrent use,
+// and a nil *Journal discards everything, so callers never need to check whether journaling is enabled.
+type Journal struct {
+	mu     sync.Mutex
+	out    io.WriteCloser
+	logger *zap.SugaredLogger
+}
+
+// New opens the journal described by cfg. It returns a nil journal if journaling is disabled.
+func New(cfg *config.JournalConfig, parentLogger *zap.Logger) (*Journal, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	return &Journal{
+		out: &lumberjack.Logger{
+			Filename:   cfg.Path,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAgeDays,
+			LocalTime:  false,
+		},
+		logger: parentLogger.With(zap.String("module", "journal")).Sugar(),
+	}, nil
+}
+
+// Record appends a record with the given payload. Failures are logged rather than returned,
+// as the journal is a diagnostic aid and must never interrupt relaying.
+func (j *Journal) Record(module string, kind Kind, data interface{}) {
+	if j == nil {
+		return
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		j.logger.Errorf("Failed to encode %s journal record: %v", kind, err)
+		return
+	}
+	line, err := json.Marshal(&Record{
+		Time:   time.Now().UTC(),
+		Module: module,
+		Kind:   kind,
+		Data:   raw,
+	})
+	if err != nil {
+		j.logger.Errorf("Failed to encode %s journal record: %v", kind, err)
+		return
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.out.Write(append(line, '\n')); err != nil {
+		j.logger.Errorf("Failed to write %s journal record: %v", kind, err)
+	}
+}
+
+// RecordBlockEvent journals a block event received from the BTC client
+func (j *Journal) RecordBlockEvent(event *types.BlockEvent) {
+	if j == nil {
+		return
+	}
+	j.Record(ModuleReporter, KindBlockEvent, &BlockEvent{
+		EventType: event.EventType,
+		Header:    NewHeader(event.Height, event.Header),
+	})
+}
+
+// RecordBTCTip journals the response of a Lorenzo BTC light client tip query
+func (j *Journal) RecordBTCTip(res *btclctypes.QueryTipResponse, err error) {
+	if j == nil {
+		return
+	}
+	tip := &Tip{Error: errString(err)}
+	if err == nil {
+		tip.Height = res.Header.Height
+		tip.Hash = res.Header.Hash.MarshalHex()
+	}
+	j.Record(ModuleReporter, KindTip, tip)
+}
+
+// Close flushes and closes the active journal file
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.out.Close()
+}
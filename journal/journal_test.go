@@ -0,0 +1,141 @@
+package journal_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+func openJournal(t *testing.T, path string) *journal.Journal {
+	t.Helper()
+	j, err := journal.New(&config.JournalConfig{Enabled: true, Path: path, MaxSizeMB: 1}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return j
+}
+
+func TestJournalRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j := openJournal(t, path)
+
+	// 15 records of 100KB exceed the 1MB at which the active file is rotated
+	padding := strings.Repeat("x", 100*1024)
+	for i := 0; i < 15; i++ {
+		j.Record(journal.ModuleReporter, journal.KindTip, &journal.Tip{Height: uint64(i), Error: padding})
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := journal.Files(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[1] != path {
+		t.Fatalf("expected a rotated file followed by the active one, got %v", files)
+	}
+
+	// the records of the rotated file come first
+	records, err := journal.ReadFiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 15 {
+		t.Fatalf("expected 15 records across the journal files, got %d", len(records))
+	}
+	for i, record := range records {
+		tip := &journal.Tip{}
+		if err := record.Decode(tip); err != nil {
+			t.Fatal(err)
+		}
+		if tip.Height != uint64(i) {
+			t.Fatalf("record %d has height %d, expected the records in the order they were written", i, tip.Height)
+		}
+	}
+}
+
+func TestReadFilesOrder(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "journal.jsonl")
+	line := func(height int) string {
+		return fmt.Sprintf(`{"module":"reporter","kind":"tip","data":{"height":%d}}`+"\n", height)
+	}
+	files := map[string]string{
+		"journal-2024-01-02T00-00-00.000.jsonl": line(1),
+		"journal-2024-01-01T00-00-00.000.jsonl": line(0),
+		"journal.jsonl":                         line(2),
+		"other.jsonl":                           line(3),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := journal.ReadFiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected the records of the 2 rotated files and the active one, got %d", len(records))
+	}
+	for i, record := range records {
+		tip := &journal.Tip{}
+		if err := record.Decode(tip); err != nil {
+			t.Fatal(err)
+		}
+		if tip.Height != uint64(i) {
+			t.Fatalf("record %d has height %d, expected the oldest file first", i, tip.Height)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(line(2)+"not json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := journal.ReadFiles(path); err == nil || !strings.Contains(err.Error(), path+":2:") {
+		t.Fatalf("expected an error pointing at the invalid line, got %v", err)
+	}
+	if _, err := journal.ReadFiles(filepath.Join(dir, "missing.jsonl")); err == nil {
+		t.Fatal("expected an error for a journal without files")
+	}
+}
+
+func TestDecodeBlockEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j := openJournal(t, path)
+	header := wire.NewBlockHeader(1, &chainhash.Hash{}, &chainhash.Hash{}, 0x1d00ffff, 7)
+	j.RecordBlockEvent(types.NewBlockEvent(types.BlockDisconnected, 42, header))
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := journal.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Module != journal.ModuleReporter || records[0].Kind != journal.KindBlockEvent {
+		t.Fatalf("expected a single block event record, got %+v", records)
+	}
+	event := &journal.BlockEvent{}
+	if err := records[0].Decode(event); err != nil {
+		t.Fatal(err)
+	}
+	ib, err := event.IndexedBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.EventType != types.BlockDisconnected || ib.Height != 42 || ib.BlockHash() != header.BlockHash() {
+		t.Fatalf("decoded event %v of block %d (%s) differs from the journaled one", event.EventType, ib.Height, ib.BlockHash())
+	}
+}
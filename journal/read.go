@@ -0,0 +1,82 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxRecordSize bounds a single journal line; bootstrap records carry a whole BTC cache
+const maxRecordSize = 64 * 1024 * 1024
+
+// Files returns the journal files belonging to the active journal at path, oldest first.
+// Rotated files are named <name>-<timestamp><ext> and sort chronologically; the active file comes last.
+func Files(path string) ([]string, error) {
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(path, ext)
+	rotated, err := filepath.Glob(prefix + "-*" + ext)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(rotated)
+
+	files := rotated
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no journal files found at %s", path)
+	}
+	return files, nil
+}
+
+// ReadFiles reads the records of all journal files belonging to the active journal at path, in order
+func ReadFiles(path string) ([]*Record, error) {
+	files, err := Files(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*Record
+	for _, file := range files {
+		fileRecords, err := ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, fileRecords...)
+	}
+	return records, nil
+}
+
+// ReadFile reads the records of a single journal file
+func ReadFile(file string) ([]*Record, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []*Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := &Record{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid journal record: %w", file, line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,133 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// Kind identifies the type of payload carried by a journal record
+type Kind string
+
+const (
+	// KindBlockEvent is a block event received from the BTC client
+	KindBlockEvent Kind = "block_event"
+	// KindBootstrap is the state of the BTC cache and Lorenzo base header after bootstrap loaded them
+	KindBootstrap Kind = "bootstrap"
+	// KindTip is the response of a Lorenzo light client tip query
+	KindTip Kind = "tip"
+	// KindSubmission is a decision about which headers to submit to Lorenzo and its outcome
+	KindSubmission Kind = "submission"
+	// KindIntervention is an action the watchdog of a reporter took because relaying stalled
+	KindIntervention Kind = "intervention"
+)
+
+// Modules writing to the journal
+const (
+	ModuleReporter    = "reporter"
+	ModuleBNBReporter = "bnbreporter"
+)
+
+// Record is a single line of the journal
+type Record struct {
+	Time   time.Time       `json:"time"`
+	Module string          `json:"module"`
+	Kind   Kind            `json:"kind"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Decode unmarshals the payload of the record into v
+func (r *Record) Decode(v interface{}) error {
+	return json.Unmarshal(r.Data, v)
+}
+
+// Header is a BTC header together with its height
+type Header struct {
+	Height int32  `json:"height"`
+	Header string `json:"header"` // hex-encoded 80-byte header
+}
+
+// BlockEvent is the payload of KindBlockEvent records
+type BlockEvent struct {
+	EventType types.EventType `json:"event_type"`
+	Header
+}
+
+// Bootstrap is the payload of KindBootstrap records
+type Bootstrap struct {
+	BaseHeight uint64   `json:"base_height"`
+	BaseHash   string   `json:"base_hash"`
+	Blocks     []Header `json:"blocks"`
+}
+
+// Tip is the payload of KindTip records
+type Tip struct {
+	Height uint64 `json:"height"`
+	Hash   string `json:"hash,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Submission is the payload of KindSubmission records
+type Submission struct {
+	// FirstHeight and LastHeight delimit the candidate headers, before deduplication against Lorenzo
+	FirstHeight uint64 `json:"first_height"`
+	LastHeight  uint64 `json:"last_height"`
+	// Submitted lists the hashes of the headers that Lorenzo accepted, in order. Error tells why the others failed.
+	Submitted []string `json:"submitted"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// Intervention is the payload of KindIntervention records
+type Intervention struct {
+	Action string `json:"action"`
+	// SourceTip is the tip of the source chain and RelayedTip the last block the reporter relayed
+	SourceTip  uint64 `json:"source_tip"`
+	RelayedTip uint64 `json:"relayed_tip"`
+	StalledFor string `json:"stalled_for"`
+	Error      string `json:"error,omitempty"`
+}
+
+// NewHeader wraps a BTC header and its height for the journal
+func NewHeader(height int32, header *wire.BlockHeader) Header {
+	var buf bytes.Buffer
+	// serializing into a bytes.Buffer cannot fail
+	_ = header.Serialize(&buf)
+	return Header{
+		Height: height,
+		Header: hex.EncodeToString(buf.Bytes()),
+	}
+}
+
+// BlockHeader decodes the journaled BTC header
+func (h *Header) BlockHeader() (*wire.BlockHeader, error) {
+	b, err := hex.DecodeString(h.Header)
+	if err != nil {
+		return nil, err
+	}
+	header := &wire.BlockHeader{}
+	if err := header.Deserialize(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// IndexedBlock decodes the journaled BTC header into an indexed block without transactions
+func (h *Header) IndexedBlock() (*types.IndexedBlock, error) {
+	header, err := h.BlockHeader()
+	if err != nil {
+		return nil, err
+	}
+	return types.NewIndexedBlock(h.Height, header, nil), nil
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type BNBReporterMetrics struct {
+	UploadedHeadersCounter       prometheus.Counter
+	FailedUploadsCounter         prometheus.Counter
+	BNBTipGauge                  prometheus.Gauge
+	LorenzoTipGauge              prometheus.Gauge
+	LagBlocksGauge               prometheus.Gauge
+	LagSecondsGauge              prometheus.Gauge
+	RPCDurationHistogramVec      *prometheus.HistogramVec
+	RPCErrorsCounterVec          *prometheus.CounterVec
+	BootstrapsCounter            prometheus.Counter
+	BatchSizeHistogram           prometheus.Histogram
+	WatchdogInterventionsCounter prometheus.Counter
+}
+
+// NewBNBReporterMetrics registers the BNB reporter metrics in the given registry, shared with other components of the process
+func NewBNBReporterMetrics(registry *prometheus.Registry) *BNBReporterMetrics {
+	registerer := promauto.With(registry)
+
+	return &BNBReporterMetrics{
+		UploadedHeadersCounter: registerer.NewCounter(prometheus.CounterOpts{
+			Name: "lrzrelayer_bnbreporter_uploaded_headers",
+			Help: "The total number of BNB headers uploaded to Lorenzo",
+		}),
+		FailedUploadsCounter: registerer.NewCounter(prometheus.CounterOpts{
+			Name: "lrzrelayer_bnbreporter_failed_uploads",
+			Help: "The total number of BNB header uploads to Lorenzo that failed",
+		}),
+		BNBTipGauge: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "lrzrelayer_bnbreporter_bnb_tip_height",
+			Help: "The height of the BNB tip at the last poll",
+		}),
+		LorenzoTipGauge: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "lrzrelayer_bnbreporter_lorenzo_tip_height",
+			Help: "The height of the tip of Lorenzo's BNB light client",
+		}),
+		LagBlocksGauge: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "lrzrelayer_bnbreporter_lag_blocks",
+			Help: "The number of blocks Lorenzo's BNB light client is behind the BNB tip",
+		}),
+		LagSecondsGauge: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "lrzrelayer_bnbreporter_lag_seconds",
+			Help: "The difference between the timestamps of the BNB tip and the tip of Lorenzo's BNB light client",
+		}),
+		RPCDurationHistogramVec: registerer.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "lrzrelayer_bnbreporter_rpc_duration_seconds",
+				Help:    "The duration of the RPC calls to the BNB node",
+				Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+			},
+			[]string{
+				// the method of the BNB client, e.g., RangeHeaders
+				"method",
+			},
+		),
+		RPCErrorsCounterVec: registerer.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "lrzrelayer_bnbreporter_rpc_errors",
+				Help: "The total number of RPC calls to the BNB node that failed",
+			},
+			[]string{"method"},
+		),
+		BootstrapsCounter: registerer.NewCounter(prometheus.CounterOpts{
+			Name: "lrzrelayer_bnbreporter_bootstraps",
+			Help: "The total number of bootstraps from the tip of Lorenzo's BNB light client",
+		}),
+		BatchSizeHistogram: registerer.NewHistogram(prometheus.HistogramOpts{
+			Name:    "lrzrelayer_bnbreporter_batch_size",
+			Help:    "The number of BNB headers in each upload to Lorenzo",
+			Buckets: []float64{1, 2, 5, 10, 20, 50, 100},
+		}),
+		WatchdogInterventionsCounter: registerer.NewCounter(prometheus.CounterOpts{
+			Name: "lrzrelayer_bnbreporter_watchdog_interventions",
+			Help: "The total number of times the watchdog reconnected to the BNB node and bootstrapped a stalled reporter",
+		}),
+	}
+}
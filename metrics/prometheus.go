@@ -1,9 +1,17 @@
 package metrics
 
 import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
-	_ "net/http/pprof"
+	"net/http/pprof"
 	"regexp"
+	"sync/atomic"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
@@ -13,30 +21,156 @@ import (
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
 )
 
-func Start(addr string, reg *prometheus.Registry) {
-	go start(addr, reg)
+// readHeaderTimeout bounds how long a client may take to send the request headers
+const readHeaderTimeout = 10 * time.Second
+
+// Server serves the metrics of a registry, and the admin and profiling endpoints, on its own mux
+type Server struct {
+	cfg    *config.MetricsConfig
+	access atomic.Pointer[config.MetricsConfig] // the auth and pprof settings, which change on config reloads
+	mux    *http.ServeMux
+	srv    *http.Server
+	ln     net.Listener
+	errs   chan error
+	logger *zap.SugaredLogger
 }
 
-func start(addr string, reg *prometheus.Registry) {
+// NewServer creates the metrics server of the given registry. It registers the Go runtime and build info collectors
+// in the registry.
+func NewServer(cfg *config.MetricsConfig, reg *prometheus.Registry, parentLogger *zap.Logger) *Server {
 	// Add Go module build info.
 	reg.MustRegister(collectors.NewBuildInfoCollector())
 	reg.MustRegister(collectors.NewGoCollector(
 		collectors.WithGoCollectorRuntimeMetrics(collectors.GoRuntimeMetricsRule{Matcher: regexp.MustCompile("/.*")})),
 	)
 
+	s := &Server{
+		cfg:    cfg,
+		mux:    http.NewServeMux(),
+		errs:   make(chan error, 1),
+		logger: parentLogger.With(zap.String("module", "metrics")).Sugar(),
+	}
 	// Expose the registered metrics via HTTP.
-	http.Handle("/metrics", promhttp.HandlerFor(
+	s.mux.Handle("/metrics", promhttp.HandlerFor(
 		reg,
 		promhttp.HandlerOpts{
 			// Opt into OpenMetrics to support exemplars.
 			EnableOpenMetrics: true,
 		},
 	))
-	metricsLogger, err := config.NewRootLogger("auto", "debug")
+	s.access.Store(cfg)
+	s.mux.Handle("/debug/pprof/", s.pprof(pprof.Index))
+	s.mux.Handle("/debug/pprof/cmdline", s.pprof(pprof.Cmdline))
+	s.mux.Handle("/debug/pprof/profile", s.pprof(pprof.Profile))
+	s.mux.Handle("/debug/pprof/symbol", s.pprof(pprof.Symbol))
+	s.mux.Handle("/debug/pprof/trace", s.pprof(pprof.Trace))
+	s.srv = &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", cfg.Host, cfg.ServerPort),
+		Handler:           s.authenticate(s.mux),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	return s
+}
+
+// UpdateConfig applies the auth and pprof settings of cfg. The address and TLS files are only set on creation.
+func (s *Server) UpdateConfig(cfg *config.MetricsConfig) {
+	s.access.Store(cfg)
+}
+
+// Handle registers a handler on the server's mux, behind the same auth as the metrics. It has to be called before Start.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start listens on the configured address and serves in the background. Errors of listening are returned,
+// later failures of the server are sent on Err.
+func (s *Server) Start() error {
+	// a bad certificate fails the start rather than the server
+	if s.cfg.TLSEnabled() {
+		cert, err := tls.LoadX509KeyPair(s.cfg.TLSCertFile, s.cfg.TLSKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		s.srv.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	}
+
+	ln, err := net.Listen("tcp", s.srv.Addr)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("failed to listen on %s: %w", s.srv.Addr, err)
 	}
-	log := metricsLogger.With(zap.String("module", "metrics")).Sugar()
-	log.Infof("Successfully started Prometheus metrics server at %s", addr)
-	log.Fatal(http.ListenAndServe(addr, nil))
+	s.ln = ln
+
+	go func() {
+		var err error
+		if s.cfg.TLSEnabled() {
+			err = s.srv.ServeTLS(ln, "", "")
+		} else {
+			err = s.srv.Serve(ln)
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			s.errs <- err
+		}
+		close(s.errs)
+	}()
+	s.logger.Infof("Successfully started Prometheus metrics server at %s (tls: %v, pprof: %v)",
+		ln.Addr(), s.cfg.TLSEnabled(), s.access.Load().Pprof)
+	return nil
+}
+
+// Addr returns the address the server listens on, once started
+func (s *Server) Addr() net.Addr {
+	return s.ln.Addr()
+}
+
+// Err returns the failure of the server after Start, if any. It is closed once the server stopped.
+func (s *Server) Err() <-chan error {
+	return s.errs
+}
+
+// Stop stops accepting connections and waits for the requests in flight until ctx is done
+func (s *Server) Stop(ctx context.Context) error {
+	if err := s.srv.Shutdown(ctx); err != nil {
+		return err
+	}
+	s.logger.Info("Metrics server shutdown")
+	return nil
+}
+
+// authenticate requires the configured basic auth or bearer token, if any
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		cfg := s.access.Load()
+		switch {
+		case cfg.Username != "":
+			user, password, ok := req.BasicAuth()
+			if !ok || !equal(user, cfg.Username) || !equal(password, cfg.Password) {
+				w.Header().Set("WWW-Authenticate", `Basic realm="lrzrelayer"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		case cfg.BearerToken != "":
+			if !equal(req.Header.Get("Authorization"), "Bearer "+cfg.BearerToken) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="lrzrelayer"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// pprof serves a profiling endpoint while pprof is enabled
+func (s *Server) pprof(handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !s.access.Load().Pprof {
+			http.NotFound(w, req)
+			return
+		}
+		handler(w, req)
+	})
+}
+
+// equal compares credentials in constant time
+func equal(given, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(given), []byte(expected)) == 1
 }
@@ -0,0 +1,65 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+)
+
+func TestServerAuth(t *testing.T) {
+	cfg := config.MetricsConfig{Host: "127.0.0.1", BearerToken: "s3cret"}
+	server := metrics.NewServer(&cfg, prometheus.NewRegistry(), zap.NewNop())
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := server.Stop(context.Background()); err != nil {
+			t.Error(err)
+		}
+	}()
+	url := "http://" + server.Addr().String()
+
+	get := func(path, token string) int {
+		req, err := http.NewRequest(http.MethodGet, url+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	if code := get("/metrics", ""); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a token, got %d", code)
+	}
+	if code := get("/metrics", "wrong"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with a wrong token, got %d", code)
+	}
+	if code := get("/metrics", "s3cret"); code != http.StatusOK {
+		t.Fatalf("expected 200 with the token, got %d", code)
+	}
+	if code := get("/debug/pprof/", "s3cret"); code != http.StatusNotFound {
+		t.Fatalf("expected pprof to be off by default, got %d", code)
+	}
+}
+
+func TestServerStartWithBadCertificate(t *testing.T) {
+	cfg := config.MetricsConfig{Host: "127.0.0.1", TLSCertFile: "missing.crt", TLSKeyFile: "missing.key"}
+	server := metrics.NewServer(&cfg, prometheus.NewRegistry(), zap.NewNop())
+	if err := server.Start(); err == nil {
+		_ = server.Stop(context.Background())
+		t.Fatal("expected a missing certificate to fail the start")
+	}
+}
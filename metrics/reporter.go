@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -8,15 +9,28 @@ import (
 )
 
 type ReporterMetrics struct {
-	Registry                    *prometheus.Registry
-	SuccessfulHeadersCounter    prometheus.Counter
-	FailedHeadersCounter        prometheus.Counter
-	SecondsSinceLastHeaderGauge prometheus.Gauge
-	NewReportedHeaderGaugeVec   *prometheus.GaugeVec
+	Registry                     *prometheus.Registry
+	SuccessfulHeadersCounter     prometheus.Counter
+	FailedHeadersCounter         prometheus.Counter
+	SecondsSinceLastHeaderGauge  prometheus.Gauge
+	BTCTipGauge                  prometheus.Gauge
+	LorenzoTipGauge              prometheus.Gauge
+	LagBlocksGauge               prometheus.Gauge
+	SubmitDurationHistogramVec   *prometheus.HistogramVec
+	HeadersPerTxHistogram        prometheus.Histogram
+	InclusionDelayHistogram      prometheus.Histogram
+	WatchdogInterventionsCounter prometheus.Counter
+
+	// lastHeader is when headers were last reported, in Unix nanoseconds. It outlives restarted reporters.
+	lastHeader atomic.Int64
 }
 
 func NewReporterMetrics() *ReporterMetrics {
-	registry := prometheus.NewRegistry()
+	return NewReporterMetricsIn(prometheus.NewRegistry())
+}
+
+// NewReporterMetricsIn registers the reporter metrics in the given registry, shared with other components of the process
+func NewReporterMetricsIn(registry *prometheus.Registry) *ReporterMetrics {
 	registerer := promauto.With(registry)
 
 	metrics := &ReporterMetrics{
@@ -33,27 +47,56 @@ func NewReporterMetrics() *ReporterMetrics {
 			Name: "lrzrelayer_reporter_since_last_header_seconds",
 			Help: "Seconds since the last successful reported BTC header to Lorenzo",
 		}),
-		NewReportedHeaderGaugeVec: registerer.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Name: "lrzrelayer_reporter_new_btc_header",
-				Help: "The metric of a new BTC header reported to Lorenzo",
+		BTCTipGauge: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "lrzrelayer_reporter_btc_tip_height",
+			Help: "The height of the BTC tip at the last query",
+		}),
+		LorenzoTipGauge: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "lrzrelayer_reporter_lorenzo_tip_height",
+			Help: "The height of the tip of Lorenzo's BTC light client",
+		}),
+		LagBlocksGauge: registerer.NewGauge(prometheus.GaugeOpts{
+			Name: "lrzrelayer_reporter_lag_blocks",
+			Help: "The number of blocks Lorenzo's BTC light client is behind the BTC tip",
+		}),
+		SubmitDurationHistogramVec: registerer.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "lrzrelayer_reporter_submit_duration_seconds",
+				Help:    "The duration of the InsertHeaders txs sent to Lorenzo",
+				Buckets: prometheus.ExponentialBuckets(0.1, 2, 10),
 			},
 			[]string{
-				// the id of the reported BTC header
-				"id",
+				// success or failure
+				"status",
 			},
 		),
+		HeadersPerTxHistogram: registerer.NewHistogram(prometheus.HistogramOpts{
+			Name:    "lrzrelayer_reporter_headers_per_tx",
+			Help:    "The number of BTC headers in the InsertHeaders txs included in Lorenzo",
+			Buckets: []float64{1, 2, 5, 10, 20, 50, 100},
+		}),
+		InclusionDelayHistogram: registerer.NewHistogram(prometheus.HistogramOpts{
+			Name:    "lrzrelayer_reporter_inclusion_delay_seconds",
+			Help:    "Seconds from the first block event of a BTC block to the inclusion of its header in Lorenzo",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 14),
+		}),
+		WatchdogInterventionsCounter: registerer.NewCounter(prometheus.CounterOpts{
+			Name: "lrzrelayer_reporter_watchdog_interventions",
+			Help: "The total number of times the watchdog re-subscribed to BTC blocks and bootstrapped a stalled reporter",
+		}),
 	}
+	metrics.lastHeader.Store(time.Now().UnixNano())
 	return metrics
 }
 
-func (sm *ReporterMetrics) RecordMetrics() {
-	go func() {
-		ticker := time.NewTicker(1 * time.Second)
-		for range ticker.C {
-			// will be reset when a header is successfully sent
-			sm.SecondsSinceLastHeaderGauge.Inc()
-		}
-	}()
+// HeadersReported records that headers were just reported to Lorenzo
+func (sm *ReporterMetrics) HeadersReported() {
+	sm.lastHeader.Store(time.Now().UnixNano())
+	sm.SecondsSinceLastHeaderGauge.Set(0)
+}
 
+// UpdateSinceLastHeader sets the seconds since headers were last reported
+func (sm *ReporterMetrics) UpdateSinceLastHeader() {
+	since := time.Since(time.Unix(0, sm.lastHeader.Load()))
+	sm.SecondsSinceLastHeaderGauge.Set(float64(since / time.Second))
 }
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type SignerMetrics struct {
+	TxsCounterVec                *prometheus.CounterVec
+	SequenceMismatchesCounterVec *prometheus.CounterVec
+	AccountSequenceGaugeVec      *prometheus.GaugeVec
+	BalanceGaugeVec              *prometheus.GaugeVec
+	PausedGaugeVec               *prometheus.GaugeVec
+}
+
+// NewSignerMetrics registers the per-key metrics of the Lorenzo signers in the given registry
+func NewSignerMetrics(registry *prometheus.Registry) *SignerMetrics {
+	registerer := promauto.With(registry)
+
+	return &SignerMetrics{
+		TxsCounterVec: registerer.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "lrzrelayer_signer_txs",
+				Help: "The total number of Lorenzo txs sent by each key",
+			},
+			[]string{
+				// the name of the key in the keyring
+				"key",
+				// success or failure
+				"status",
+			},
+		),
+		SequenceMismatchesCounterVec: registerer.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "lrzrelayer_signer_sequence_mismatches",
+				Help: "The total number of account sequence mismatches of each key",
+			},
+			[]string{"key"},
+		),
+		AccountSequenceGaugeVec: registerer.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "lrzrelayer_signer_account_sequence",
+				Help: "The account sequence each key is expected to sign its next tx with",
+			},
+			[]string{"key"},
+		),
+		BalanceGaugeVec: registerer.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "lrzrelayer_signer_balance",
+				Help: "The balance of each key in the denom of the balance thresholds",
+			},
+			[]string{"key", "denom"},
+		),
+		PausedGaugeVec: registerer.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "lrzrelayer_signer_paused",
+				Help: "1 if a reporter's broadcasting is paused because all its keys are below the critical balance, 0 otherwise",
+			},
+			[]string{"reporter"},
+		),
+	}
+}
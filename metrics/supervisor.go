@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type SupervisorMetrics struct {
+	RestartsCounterVec *prometheus.CounterVec
+	FailuresCounterVec *prometheus.CounterVec
+	UpGaugeVec         *prometheus.GaugeVec
+}
+
+// NewSupervisorMetrics registers the metrics of the services run by the supervisor in the given registry
+func NewSupervisorMetrics(registry *prometheus.Registry) *SupervisorMetrics {
+	registerer := promauto.With(registry)
+
+	return &SupervisorMetrics{
+		RestartsCounterVec: registerer.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "lrzrelayer_supervisor_restarts",
+				Help: "The total number of restarts of each service after a failure",
+			},
+			[]string{"service"},
+		),
+		FailuresCounterVec: registerer.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "lrzrelayer_supervisor_failures",
+				Help: "The total number of failures of each service by error class",
+			},
+			[]string{
+				"service",
+				// the error class, see types.ClassOf
+				"class",
+			},
+		),
+		UpGaugeVec: registerer.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "lrzrelayer_supervisor_service_up",
+				Help: "1 if a service is running, 0 if it failed and waits for a restart",
+			},
+			[]string{"service"},
+		),
+	}
+}
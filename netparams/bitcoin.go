@@ -2,32 +2,106 @@ package netparams
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/btcsuite/btcd/chaincfg"
 
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
-func GetBTCParams(net string) (*chaincfg.Params, error) {
-	switch net {
-	case types.BtcMainnet.String():
-		return &chaincfg.MainNetParams, nil
-	case types.BtcTestnet.String():
-		return &chaincfg.TestNet3Params, nil
-	case types.BtcSimnet.String():
-		return &chaincfg.SimNetParams, nil
-	case types.BtcRegtest.String():
-		return &chaincfg.RegressionNetParams, nil
-	case types.BtcSignet.String():
-		return &chaincfg.SigNetParams, nil
+var (
+	// builtinParams maps the names of the networks known without configuration to their parameters
+	builtinParams = map[string]*chaincfg.Params{
+		types.BtcMainnet.String():  &chaincfg.MainNetParams,
+		types.BtcTestnet.String():  &chaincfg.TestNet3Params,
+		types.BtcTestnet4.String(): &TestNet4Params,
+		types.BtcSimnet.String():   &chaincfg.SimNetParams,
+		types.BtcRegtest.String():  &chaincfg.RegressionNetParams,
+		types.BtcSignet.String():   &chaincfg.SigNetParams,
+	}
+	builtin = types.GetValidNetParams()
+)
+
+// Registry holds the parameters of the built-in networks and of the custom networks of a config.
+// A nil registry knows the built-in networks only.
+type Registry struct {
+	custom map[string]*chaincfg.Params
+}
+
+// NewRegistry returns a registry of the built-in networks and the given custom ones, which can't redefine a
+// built-in network
+func NewRegistry(custom map[string]*chaincfg.Params) (*Registry, error) {
+	r := &Registry{custom: make(map[string]*chaincfg.Params, len(custom))}
+	for name, params := range custom {
+		if builtin[name] {
+			return nil, fmt.Errorf("BTC network %s is built-in and can't be redefined", name)
+		}
+		r.custom[name] = params
 	}
+	return r, nil
+}
+
+// IsBuiltin returns whether the network is one of the networks known without configuration
+func IsBuiltin(net string) bool {
+	return builtin[net]
+}
+
+// Exists returns whether the network is built-in or one of the custom networks of the registry
+func (r *Registry) Exists(net string) bool {
+	_, err := r.GetBTCParams(net)
+	return err == nil
+}
+
+// GetBTCParams returns the parameters of a built-in or custom network
+func (r *Registry) GetBTCParams(net string) (*chaincfg.Params, error) {
+	if params, ok := builtinParams[net]; ok {
+		return params, nil
+	}
+	if r != nil {
+		if params, ok := r.custom[net]; ok {
+			return params, nil
+		}
+	}
+
+	names := make([]string, 0, len(builtinParams))
+	for name := range builtinParams {
+		names = append(names, name)
+	}
+	if r != nil {
+		for name := range r.custom {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
 	return nil, fmt.Errorf(
-		"BTC network with name %s does not exist. should be one of {%s, %s, %s, %s, %s}",
+		"BTC network with name %s does not exist. should be one of {%s}",
 		net,
-		types.BtcMainnet.String(),
-		types.BtcTestnet.String(),
-		types.BtcSimnet.String(),
-		types.BtcRegtest.String(),
-		types.BtcSignet.String(),
+		strings.Join(names, ", "),
 	)
 }
+
+// GetBTCParams returns the parameters of a built-in network
+func GetBTCParams(net string) (*chaincfg.Params, error) {
+	return (*Registry)(nil).GetBTCParams(net)
+}
+
+// ChainNames returns the chain names bitcoind and btcd report in getblockchaininfo for networks sharing the genesis
+// block of the given params, e.g., custom signets report "signet". It returns nil for networks with an unknown genesis.
+func ChainNames(params *chaincfg.Params) []string {
+	switch *params.GenesisHash {
+	case *chaincfg.MainNetParams.GenesisHash:
+		return []string{"main", "mainnet"}
+	case *chaincfg.TestNet3Params.GenesisHash:
+		return []string{"test", "testnet3"}
+	case *TestNet4Params.GenesisHash:
+		return []string{"testnet4"}
+	case *chaincfg.SimNetParams.GenesisHash:
+		return []string{"simnet"}
+	case *chaincfg.RegressionNetParams.GenesisHash:
+		return []string{"regtest"}
+	case *chaincfg.SigNetParams.GenesisHash:
+		return []string{"signet"}
+	}
+	return nil
+}
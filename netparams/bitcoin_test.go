@@ -0,0 +1,60 @@
+package netparams_test
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/netparams"
+)
+
+func TestBuiltinGenesisHashes(t *testing.T) {
+	genesis := map[string]string{
+		"mainnet":  "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f",
+		"testnet":  "000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943",
+		"testnet4": "00000000da84f2bafbbc53dee25a72ae507ff4914b867c565be350b0da8bf043",
+		"simnet":   "683e86bd5c6d110d91b94b97137ba6bfe02dbbdb8e3dff722a669b5d69d77af6",
+		"regtest":  "0f9188f13cb7b2c71f2a335e3a4fc328bf5beb436012afca590b1a11466e2206",
+		"signet":   "00000008819873e925422c1ff0f99f7cc9bbb232af63a077a480a3633bee1ef6",
+	}
+	for net, hash := range genesis {
+		params, err := netparams.GetBTCParams(net)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if params.GenesisHash.String() != hash {
+			t.Errorf("unexpected %s genesis hash %s", net, params.GenesisHash)
+		}
+		if blockHash := params.GenesisBlock.BlockHash(); !blockHash.IsEqual(params.GenesisHash) {
+			t.Errorf("%s genesis block hashes to %s, not to its genesis hash", net, blockHash)
+		}
+		if !netparams.IsBuiltin(net) || len(netparams.ChainNames(params)) == 0 {
+			t.Errorf("expected %s to be a built-in network with chain names", net)
+		}
+	}
+	if _, err := netparams.GetBTCParams("testnet5"); err == nil {
+		t.Fatal("expected error on an unknown network")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	custom := chaincfg.RegressionNetParams
+	custom.Name = "devnet"
+	registry, err := netparams.NewRegistry(map[string]*chaincfg.Params{"devnet": &custom})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params, err := registry.GetBTCParams("devnet"); err != nil || params != &custom {
+		t.Fatalf("expected the custom network to be resolved, got %v", err)
+	}
+	if params, err := registry.GetBTCParams("regtest"); err != nil || params != &chaincfg.RegressionNetParams {
+		t.Fatalf("expected the built-in networks to be resolved, got %v", err)
+	}
+	if !registry.Exists("devnet") || (*netparams.Registry)(nil).Exists("devnet") {
+		t.Fatal("expected the custom network to exist in its registry only")
+	}
+
+	if _, err := netparams.NewRegistry(map[string]*chaincfg.Params{"regtest": &custom}); err == nil {
+		t.Fatal("expected error on redefining a built-in network")
+	}
+}
@@ -0,0 +1,73 @@
+package netparams
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// testNet4GenesisCoinbaseTx is the coinbase transaction of the testnet4 genesis block (BIP94)
+var testNet4GenesisCoinbaseTx = wire.MsgTx{
+	Version: 1,
+	TxIn: []*wire.TxIn{{
+		PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{}, Index: 0xffffffff},
+		SignatureScript: append(
+			mustDecodeHex("04ffff001d01044c4c"),
+			[]byte("03/May/2024 000000000000000000001ebd58c244970b3aa9d783bb001011fbe8ea8e98e00e")...,
+		),
+		Sequence: 0xffffffff,
+	}},
+	TxOut: []*wire.TxOut{{
+		Value: 0x12a05f200, // 50 BTC
+		// 33 zero bytes pushed as public key, followed by OP_CHECKSIG
+		PkScript: append(append([]byte{0x21}, make([]byte, 33)...), 0xac),
+	}},
+	LockTime: 0,
+}
+
+// testNet4GenesisBlock is the genesis block of testnet4
+var testNet4GenesisBlock = wire.MsgBlock{
+	Header: wire.BlockHeader{
+		Version:    1,
+		PrevBlock:  chainhash.Hash{},
+		MerkleRoot: testNet4GenesisCoinbaseTx.TxHash(), // 7aa0a7ae1e223414cb807e40cd57e667b718e42aaf9306db9102fe28912b7b4e
+		Timestamp:  time.Unix(1714777860, 0),           // 2024-05-03 23:11:00 +0000 UTC
+		Bits:       0x1d00ffff,
+		Nonce:      393743547,
+	},
+	Transactions: []*wire.MsgTx{&testNet4GenesisCoinbaseTx},
+}
+
+// testNet4GenesisHash is 00000000da84f2bafbbc53dee25a72ae507ff4914b867c565be350b0da8bf043
+var testNet4GenesisHash = testNet4GenesisBlock.BlockHash()
+
+// TestNet4Params defines the network parameters of testnet4, which the pinned btcd version does not know.
+// They are the testnet3 parameters with the testnet4 genesis block, magic, port and seeds.
+var TestNet4Params = func() chaincfg.Params {
+	params := chaincfg.TestNet3Params
+	params.Name = "testnet4"
+	params.Net = wire.BitcoinNet(0x283f161c)
+	params.DefaultPort = "48333"
+	params.DNSSeeds = []chaincfg.DNSSeed{
+		{Host: "seed.testnet4.bitcoin.sprovoost.nl", HasFiltering: true},
+		{Host: "seed.testnet4.wiz.biz", HasFiltering: true},
+	}
+	params.GenesisBlock = &testNet4GenesisBlock
+	params.GenesisHash = &testNet4GenesisHash
+	params.BIP0034Height = 1
+	params.BIP0065Height = 1
+	params.BIP0066Height = 1
+	params.Checkpoints = nil
+	return params
+}()
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,45 @@
+package reporter
+
+import (
+	"context"
+
+	btclctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/audit"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+)
+
+// auditInsertHeaders records the result of submitting the headers of msg, starting at firstHeight, to the audit log
+func (r *Reporter) auditInsertHeaders(ctx context.Context, msg *btclctypes.MsgInsertHeaders, signer string, firstHeight uint64, res *pv.RelayerTxResponse, err error) {
+	if r.audit == nil {
+		return
+	}
+	entry := &audit.Entry{
+		Module:      journal.ModuleReporter,
+		Msg:         audit.MsgInsertHeaders,
+		Reason:      audit.ReasonFrom(ctx),
+		FirstHeight: firstHeight,
+		LastHeight:  firstHeight + uint64(len(msg.Headers)) - 1,
+		Hashes:      make([]string, 0, len(msg.Headers)),
+		Signer:      signer,
+	}
+	for _, header := range msg.Headers {
+		entry.Hashes = append(entry.Hashes, header.Hash().MarshalHex())
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if res != nil {
+		entry.TxHash, entry.Code = res.TxHash, res.Code
+		// only a tx included in a block used gas, its height is set once the broadcast saw it committed
+		if res.TxHash != "" && res.Height > 0 {
+			gasUsed, gasErr := r.lorenzoClient.TxGasUsed(ctx, res.TxHash)
+			if gasErr != nil {
+				r.logger.Warnf("Failed to query gas used by tx %s: %v", res.TxHash, gasErr)
+			}
+			entry.GasUsed = gasUsed
+		}
+	}
+	r.audit.Record(entry)
+}
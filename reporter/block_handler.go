@@ -1,224 +1,308 @@
 package reporter
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/tracing"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
 const (
 	BlockEventCheckInterval = time.Minute
+	// PausedCheckInterval is how often a reporter holding back submissions checks whether its signers resumed
+	PausedCheckInterval = 30 * time.Second
 )
 
 // blockEventHandler handles connected and disconnected blocks from the BTC client.
 func (r *Reporter) blockEventHandler() {
 	defer r.wg.Done()
+	defer r.recoverFailure()
 	quit := r.quitChan()
+	watchdog := time.NewTicker(WatchdogCheckInterval)
+	defer watchdog.Stop()
 
 	for {
 		select {
 		case event, open := <-r.btcClient.BlockEventChan():
-			//delay block processing until the block is mature
-			for {
-				select {
-				case <-quit:
-					return
-				default:
-				}
-
-				_, h, err := r.btcClient.GetBestBlock()
-				if err != nil {
-					r.logger.Warnf("Failed to get best block from BTC client: %v", err)
-					time.Sleep(time.Second)
-					continue
-				}
-				if h >= r.delayBlocks+uint64(event.Height) {
-					break
-				}
-				r.logger.Debugf("Delaying block processing for %d blocks. blockHeight: %d, btcTip: %d",
-					r.delayBlocks, event.Height, h)
-				time.Sleep(BlockEventCheckInterval)
-			}
-
 			if !open {
 				r.logger.Errorf("Block event channel is closed")
 				return // channel closed
 			}
-
-			var errorRequiringBootstrap error
+			r.journal.RecordBlockEvent(event)
 			if event.EventType == types.BlockConnected {
-				errorRequiringBootstrap = r.handleConnectedBlocks(event)
-			} else if event.EventType == types.BlockDisconnected {
-				errorRequiringBootstrap = r.handleDisconnectedBlocks(event)
+				r.recordFirstSeen(event.Header.BlockHash())
 			}
 
+			ctx, span := tracer.Start(r.quitCtx(), "reporter.BlockEvent", trace.WithAttributes(
+				tracing.EventKey.String(event.EventType.String()),
+				tracing.HeightKey.Int64(int64(event.Height)),
+				tracing.HashKey.String(event.Header.BlockHash().String()),
+			))
+			if !r.waitUntilMature(ctx, event) {
+				span.End()
+				r.flushOnStop()
+				return
+			}
+			errorRequiringBootstrap := r.handleBlockEvent(ctx, event)
+			tracing.End(span, errorRequiringBootstrap)
 			if errorRequiringBootstrap != nil {
 				r.logger.Warnf("Due to error in event processing: %v, bootstrap process need to be restarted", errorRequiringBootstrap)
-				r.bootstrapWithRetries(true)
+				if err := r.bootstrapWithRetries(true); err != nil {
+					r.fail(err)
+					return
+				}
+			}
+
+		case <-r.submitTimer():
+			r.flushSubmitQueue(r.quitCtx())
+
+		case <-r.reloadChan:
+			r.applyPendingConfig()
+
+		case <-watchdog.C:
+			if err := r.checkStall(r.quitCtx()); err != nil {
+				r.fail(err)
+				return
+			}
+
+		case <-r.degradedProbeTimer():
+			if errorRequiringBootstrap := r.probeLorenzo(); errorRequiringBootstrap != nil {
+				r.logger.Warnf("Due to error in recovering from Lorenzo outage: %v, bootstrap process need to be restarted", errorRequiringBootstrap)
+				if err := r.bootstrapWithRetries(true); err != nil {
+					r.fail(err)
+					return
+				}
 			}
 
 		case <-quit:
 			// We have been asked to stop
+			r.flushOnStop()
 			return
 		}
 	}
 }
 
-// handleConnectedBlocks handles connected blocks from the BTC client.
-func (r *Reporter) handleConnectedBlocks(event *types.BlockEvent) error {
-	// After delay blocks, hope the connected block is on the best chain, otherwise restart bootstrap.
-	// It is just to reduce branching on Lorenzo side.
-	{
-		ib, _, err := r.btcClient.GetBlockByHeight(uint64(event.Height))
+// waitUntilMature delays the processing of a block event until the block is mature.
+// It returns false if the reporter is asked to stop meanwhile.
+func (r *Reporter) waitUntilMature(ctx context.Context, event *types.BlockEvent) bool {
+	ctx, span := tracer.Start(ctx, "reporter.WaitMaturity")
+	defer span.End()
+	quit := r.quitChan()
+
+	for {
+		select {
+		case <-quit:
+			return false
+		default:
+		}
+
+		_, h, err := r.btcClient.GetBestBlock(ctx)
 		if err != nil {
-			return err
+			r.logger.Warnf("Failed to get best block from BTC client: %v", err)
+			time.Sleep(time.Second)
+			continue
 		}
-		eventBlockHash := event.Header.BlockHash()
-		ibBlockHash := ib.BlockHash()
-		if !eventBlockHash.IsEqual(&ibBlockHash) {
-			return fmt.Errorf("connected block[%s] isn't on the best chain", eventBlockHash.String())
+		r.recordBTCTip(h)
+		if h >= r.delayBlocks+uint64(event.Height) {
+			span.SetAttributes(tracing.TipKey.Int64(int64(h)))
+			return true
+		}
+		r.logger.Debugf("Delaying block processing for %d blocks. blockHeight: %d, btcTip: %d",
+			r.delayBlocks, event.Height, h)
+		// no more headers will be queued while we wait
+		r.flushSubmitQueue(ctx)
+		select {
+		case <-quit:
+			return false
+		case <-r.reloadChan:
+			r.applyPendingConfig()
+		case <-time.After(BlockEventCheckInterval):
 		}
 	}
+}
+
+// handleBlockEvent dispatches a mature block event. It returns an error if the event
+// cannot be reconciled with the cache and bootstrap is required.
+func (r *Reporter) handleBlockEvent(ctx context.Context, event *types.BlockEvent) error {
+	switch event.EventType {
+	case types.BlockConnected:
+		return r.handleConnectedBlocks(ctx, event)
+	case types.BlockDisconnected:
+		return r.handleDisconnectedBlocks(ctx, event)
+	}
+	return nil
+}
+
+// handleConnectedBlocks handles connected blocks from the BTC client.
+func (r *Reporter) handleConnectedBlocks(ctx context.Context, event *types.BlockEvent) error {
+	if r.headerTree == nil {
+		return fmt.Errorf("header tree is empty, restart bootstrap process")
+	}
 
 	// if the header is too early, ignore it
 	// NOTE: this might happen when bootstrapping is triggered after the reporter
 	// has subscribed to the BTC blocks
-	firstCacheBlock := r.btcCache.First()
-	if firstCacheBlock == nil {
-		return fmt.Errorf("cache is empty, restart bootstrap process")
-	}
-	if event.Height < firstCacheBlock.Height {
+	blockHash := event.Header.BlockHash()
+	if root := r.headerTree.Root(); event.Height <= root.Height {
 		r.logger.Debugf(
 			"the connecting block (height: %d, hash: %s) is too early, skipping the block",
 			event.Height,
-			event.Header.BlockHash().String(),
+			blockHash.String(),
 		)
 		return nil
 	}
 
-	// if the received header is within the cache's region, then this means the events have
-	// an overlap with the cache. Then, perform a consistency check. If the block is duplicated,
-	// then ignore the block, otherwise there is an inconsistency and redo bootstrap
-	// NOTE: this might happen when bootstrapping is triggered after the reporter
-	// has subscribed to the BTC blocks
-	if b := r.btcCache.FindBlock(uint64(event.Height)); b != nil {
-		if b.BlockHash() == event.Header.BlockHash() {
-			r.logger.Debugf(
-				"the connecting block (height: %d, hash: %s) is known to cache, skipping the block",
-				b.Height,
-				b.BlockHash().String(),
-			)
-			return nil
-		}
-		return fmt.Errorf(
-			"the connecting block (height: %d, hash: %s) is different from the header (height: %d, hash: %s) at the same height in cache",
+	// After delay blocks, hope the connected block is on the best chain, otherwise skip it.
+	// It is just to reduce branching on Lorenzo side, the BTC node disconnects the block later on.
+	fetchCtx, span := tracer.Start(ctx, "reporter.FetchBlock", trace.WithAttributes(tracing.HeightKey.Int64(int64(event.Height))))
+	ib, _, err := r.btcClient.GetBlockByHeight(fetchCtx, uint64(event.Height))
+	tracing.End(span, err)
+	if err != nil {
+		return err
+	}
+	if ib.BlockHash() != blockHash {
+		r.logger.Debugf(
+			"the connecting block (height: %d, hash: %s) isn't on the best chain, skipping the block",
 			event.Height,
-			event.Header.BlockHash().String(),
-			b.Height,
-			b.BlockHash().String(),
+			blockHash.String(),
 		)
+		return nil
 	}
 
-	// get the block from hash
-	blockHash := event.Header.BlockHash()
-	ib, mBlock, err := r.btcClient.GetBlockByHash(&blockHash)
-	if err != nil {
-		return fmt.Errorf("failed to get block %v with number %d ,from BTC client: %w", blockHash, event.Height, err)
+	if err := r.addToHeaderTree(ctx, ib); err != nil {
+		return err
 	}
-
-	// if the parent of the block is not the tip of the cache, then the cache is not up-to-date,
-	// and we might have missed some blocks. In this case, restart the bootstrap process.
-	parentHash := mBlock.Header.PrevBlock
-	cacheTip := r.btcCache.Tip() // NOTE: cache is guaranteed to be non-empty at this stage
-	if parentHash != cacheTip.BlockHash() {
-		return fmt.Errorf("cache (tip %d) is not up-to-date while connecting block %d, restart bootstrap process", cacheTip.Height, ib.Height)
+	ibs, err := r.syncCacheToBestChain()
+	if err != nil {
+		return err
 	}
+	return r.submitBestChain(ctx, ibs)
+}
 
-	// otherwise, add the block to the cache
-	r.btcCache.Add(ib)
-
-	var headersToProcess []*types.IndexedBlock
-
-	if r.reorgList.size() > 0 {
-		// we are in the middle of reorg, we need to check whether we already have all blocks of better chain
-		// as reorgs in btc nodes happen only when better chain is available.
-		// 1. First we get oldest header from our reorg branch
-		// 2. Then we get all headers from our cache starting the height of the oldest header of new branch
-		// 3. then we calculate if work on new branch starting from the first reorged height is larger
-		// than removed branch work.
-		oldestBlockFromOldBranch := r.reorgList.getLastRemovedBlock()
-		currentBranch, err := r.btcCache.GetLastBlocks(oldestBlockFromOldBranch.height)
+// addToHeaderTree adds a block of the BTC best chain to the header tree, together with the
+// ancestors missed by the reporter, e.g., when events were dropped.
+func (r *Reporter) addToHeaderTree(ctx context.Context, ib *types.IndexedBlock) error {
+	root := r.headerTree.Root()
+	branch := []*types.IndexedBlock{ib}
+	for parentHash := ib.Header.PrevBlock; !r.headerTree.Contains(&parentHash); parentHash = branch[0].Header.PrevBlock {
+		if branch[0].Height-1 <= root.Height {
+			return fmt.Errorf("block %d (%s) does not connect to the header tree (root %d), restart bootstrap process",
+				ib.Height, ib.BlockHash(), root.Height)
+		}
+		fetchCtx, span := tracer.Start(ctx, "reporter.FetchBlock", trace.WithAttributes(tracing.HashKey.String(parentHash.String())))
+		parent, _, err := r.btcClient.GetBlockByHash(fetchCtx, &parentHash)
+		tracing.End(span, err)
 		if err != nil {
-			panic(fmt.Errorf("failed to get block from cache after reorg: %w", err))
+			return fmt.Errorf("failed to get block %v from BTC client: %w", parentHash, err)
 		}
+		branch = append([]*types.IndexedBlock{parent}, branch...)
+	}
+	if len(branch) > 1 {
+		r.logger.Debugf("Fetched %d blocks missed before block %d", len(branch)-1, ib.Height)
+	}
 
-		currentBranchWork := calculateBranchWork(currentBranch)
-
-		// if current branch is better than reorg branch, we can submit headers and clear reorg list
-		if currentBranchWork.GT(r.reorgList.removedBranchWork()) {
-			r.logger.Debugf("Current branch is better than reorg branch. Length of current branch: %d, work of branch: %s", len(currentBranch), currentBranchWork)
-			headersToProcess = append(headersToProcess, currentBranch...)
-			r.reorgList.clear()
-		}
-	} else {
-		lorenzoTip, err := r.lorenzoClient.BTCHeaderChainTip()
-		if err != nil {
+	for _, b := range branch {
+		if _, err := r.headerTree.Add(b); err != nil {
 			return err
 		}
-		// after bootstrap, btcCache tip must be higher than lorenzo BTC Header tip
-		// so we make lorenzo BTC Header tip catch up
-		if lorenzoTip.Header.Height < uint64(ib.Height-1) {
-			ibs, err := r.btcCache.GetLastBlocks(lorenzoTip.Header.Height + 1)
-			if err != nil {
-				return err
-			}
-			headersToProcess = append(headersToProcess, ibs...)
-		} else {
-			headersToProcess = append(headersToProcess, ib)
+	}
+	r.headerTree.Prune(r.btcConfirmationDepth + r.checkpointFinalizationTimeout)
+	return nil
+}
+
+// syncCacheToBestChain rewinds the cache to the fork point with the best chain of the header tree,
+// then appends the best chain. It returns the appended blocks.
+func (r *Reporter) syncCacheToBestChain() ([]*types.IndexedBlock, error) {
+	tip := r.btcCache.Tip()
+	reorgDepth := 0
+	for tip != nil && !r.headerTree.OnBestChain(tip) {
+		r.logger.Debugf("Block %d (%s) left the best chain", tip.Height, tip.BlockHash())
+		if err := r.btcCache.RemoveLast(); err != nil {
+			return nil, fmt.Errorf("failed to rewind BTC cache to the best chain: %w", err)
 		}
+		reorgDepth++
+		tip = r.btcCache.Tip()
+	}
+
+	var ibs []*types.IndexedBlock
+	if tip == nil {
+		ibs = r.headerTree.BestChainFrom(r.headerTree.Root().Height)
+	} else {
+		ibs = r.headerTree.BestChainFrom(tip.Height + 1)
+	}
+	for _, ib := range ibs {
+		r.btcCache.Add(ib)
 	}
+	if reorgDepth > 0 && len(ibs) > 0 {
+		newTip := ibs[len(ibs)-1]
+		r.alerts.Reorg(journal.ModuleReporter, reorgDepth, int64(ibs[0].Height)-1, newTip.BlockHash().String())
+	}
+	return ibs, nil
+}
 
-	if len(headersToProcess) == 0 {
+// submitBestChain queues the blocks that joined the best chain for submission, together with
+// the blocks of the cache Lorenzo misses.
+func (r *Reporter) submitBestChain(ctx context.Context, ibs []*types.IndexedBlock) error {
+	if len(ibs) == 0 {
 		r.logger.Debug("No new headers to submit to Lorenzo")
 		return nil
 	}
+	if r.degraded != nil {
+		// Lorenzo is unreachable, the blocks stay buffered in the cache until it is back
+		return nil
+	}
 
-	// extracts and submits headers for each blocks in ibs
-	signer := r.lorenzoClient.MustGetAddr()
-	_, err = r.ProcessHeaders(signer, headersToProcess)
+	lorenzoTip, err := r.queryLorenzoTip()
 	if err != nil {
-		r.logger.Warnf("Failed to submit header: %v", err)
+		r.enterDegraded(err)
+		return nil
+	}
+	// after bootstrap, btcCache tip must be higher than lorenzo BTC Header tip
+	// so we make lorenzo BTC Header tip catch up
+	headersToProcess := ibs
+	if lorenzoTip.Header.Height < uint64(ibs[0].Height-1) {
+		headersToProcess, err = r.btcCache.GetLastBlocks(lorenzoTip.Header.Height + 1)
+		if err != nil {
+			return err
+		}
 	}
 
-	return nil
+	// queue the headers so that headers of consecutive events are submitted together.
+	// A checkpoint mismatch means the BTC node has followed a chain we do not trust, so make bootstrap re-verify it
+	return r.enqueueHeaders(ctx, headersToProcess)
 }
 
 // handleDisconnectedBlocks handles disconnected blocks from the BTC client.
-func (r *Reporter) handleDisconnectedBlocks(event *types.BlockEvent) error {
-	// get cache tip
-	cacheTip := r.btcCache.Tip()
-	if cacheTip == nil {
-		return fmt.Errorf("cache is empty, restart bootstrap process")
+func (r *Reporter) handleDisconnectedBlocks(ctx context.Context, event *types.BlockEvent) error {
+	if r.headerTree == nil {
+		return fmt.Errorf("header tree is empty, restart bootstrap process")
 	}
 
-	// if the block to be disconnected is not the tip of the cache, then the cache is not up-to-date,
-	if event.Header.BlockHash() != cacheTip.BlockHash() {
-		return fmt.Errorf("cache is not up-to-date while disconnecting block, restart bootstrap process")
+	// the block was skipped or pruned, there is nothing to undo
+	blockHash := event.Header.BlockHash()
+	if !r.headerTree.Contains(&blockHash) {
+		r.logger.Debugf(
+			"the disconnecting block (height: %d, hash: %s) is unknown, skipping the block",
+			event.Height,
+			blockHash.String(),
+		)
+		return nil
 	}
 
-	// at this point, the block to be disconnected is the tip of the cache so we can
-	// add it to our reorg list
-	r.reorgList.addRemovedBlock(
-		uint64(cacheTip.Height),
-		cacheTip.Header,
-	)
+	// submit the pending headers before they leave the best chain, Lorenzo tracks forks by itself
+	r.flushSubmitQueue(ctx)
 
-	// otherwise, remove the block from the cache
-	if err := r.btcCache.RemoveLast(); err != nil {
-		r.logger.Warnf("Failed to remove last block from cache: %v, restart bootstrap process", err)
-		panic(err)
+	if !r.headerTree.Disconnect(&blockHash) {
+		return nil
 	}
-
-	return nil
+	ibs, err := r.syncCacheToBestChain()
+	if err != nil {
+		return err
+	}
+	return r.submitBestChain(ctx, ibs)
 }
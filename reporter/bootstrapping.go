@@ -9,6 +9,8 @@ import (
 	"github.com/avast/retry-go/v4"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/audit"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
@@ -34,13 +36,13 @@ type consistencyCheckInfo struct {
 // of view of both chains.
 func (r *Reporter) checkConsistency() (*consistencyCheckInfo, error) {
 
-	tipRes, err := r.lorenzoClient.BTCHeaderChainTip()
+	tipRes, err := r.queryLorenzoTip()
 	if err != nil {
 		return nil, err
 	}
 
 	// Find the base height of Lorenzo header chain
-	baseRes, err := r.lorenzoClient.BTCBaseHeader()
+	baseRes, err := r.lorenzoClient.BTCBaseHeader(r.quitCtx())
 	if err != nil {
 		return nil, err
 	}
@@ -75,14 +77,29 @@ func (r *Reporter) bootstrap(skipBlockSubscription bool) error {
 		err                  error
 	)
 
-	// if we are bootstraping, we will definitely not handle reorgs
-	r.reorgList.clear()
+	// bootstrap needs Lorenzo, so once it succeeds there is no outage to recover from
+	r.degraded = nil
+
+	// bootstrap submits every header Lorenzo misses, including the pending ones
+	if pending := r.submitQueue.take(); len(pending) > 0 {
+		r.logger.Debugf("Dropped %d pending headers, bootstrap will submit them", len(pending))
+	}
+
+	// the BTC node may have restarted into initial block download since preflight, don't relay its partial chain
+	if !r.waitUntilBTCNodeSynced() {
+		return nil
+	}
 
 	// ensure BTC has caught up with Lorenzo header chain
 	if err := r.waitUntilBTCSync(); err != nil {
 		return err
 	}
 
+	// refuse to bootstrap from a BTC node that disagrees with any trusted checkpoint
+	if err := r.verifyCheckpoints(); err != nil {
+		return err
+	}
+
 	// initialize cache with the latest blocks
 	if err := r.initBTCCache(); err != nil {
 		return err
@@ -103,7 +120,7 @@ func (r *Reporter) bootstrap(skipBlockSubscription bool) error {
 
 	ibs, err = r.btcCache.GetLastBlocks(consistencyInfo.startSyncHeight)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	signer := r.lorenzoClient.MustGetAddr()
@@ -115,7 +132,10 @@ func (r *Reporter) bootstrap(skipBlockSubscription bool) error {
 	// we already checked for consistency, we can be sure that even if rest of the block headers is different from in Lorenzo
 	// due to reorg, our fork will be better than the one in Lorenzo.
 	ibs = ibs[:len(ibs)-int(r.delayBlocks)] //only process the last delayBlocks
-	_, err = r.ProcessHeaders(signer, ibs)
+	if !r.waitUntilUnpaused() {
+		return nil
+	}
+	_, err = r.ProcessHeaders(audit.WithReason(r.quitCtx(), audit.ReasonBootstrap), signer, ibs)
 	if err != nil {
 		// this can happen when there are two contentious lrzrelayer or if our btc node is behind.
 		r.logger.Errorf("Failed to submit headers: %v", err)
@@ -126,36 +146,25 @@ func (r *Reporter) bootstrap(skipBlockSubscription bool) error {
 	// trim cache to the latest k+w blocks on BTC (which are same as in Lorenzo)
 	maxEntries := r.btcConfirmationDepth + r.checkpointFinalizationTimeout
 	if err = r.btcCache.Resize(maxEntries); err != nil {
-		r.logger.Errorf("Failed to resize BTC cache: %v", err)
-		panic(err)
+		return fmt.Errorf("failed to resize BTC cache: %w", err)
 	}
 	r.btcCache.Trim()
+	r.headerTree.Prune(maxEntries)
 
 	r.logger.Infof("Size of the BTC cache: %d", r.btcCache.Size())
 
+	r.alerts.Bootstrapped(journal.ModuleReporter)
 	r.logger.Info("Successfully finished bootstrapping")
 	return nil
 }
 
 func (r *Reporter) reporterQuitCtx() (context.Context, func()) {
-	quit := r.quitChan()
-	ctx, cancel := context.WithCancel(context.Background())
-	r.wg.Add(1)
-	go func() {
-		defer cancel()
-		defer r.wg.Done()
-
-		select {
-		case <-quit:
-
-		case <-ctx.Done():
-		}
-	}()
-
-	return ctx, cancel
+	return context.WithCancel(r.quitCtx())
 }
 
-func (r *Reporter) bootstrapWithRetries(skipBlockSubscription bool) {
+// bootstrapWithRetries bootstraps until it succeeds or the attempts run out, and returns the last failure in
+// the latter case. It returns nil if the reporter is stopped meanwhile.
+func (r *Reporter) bootstrapWithRetries(skipBlockSubscription bool) error {
 	// if we are exiting, we need to cancel this process
 	ctx, cancel := r.reporterQuitCtx()
 	defer cancel()
@@ -168,16 +177,21 @@ func (r *Reporter) bootstrapWithRetries(skipBlockSubscription bool) {
 		bootstrapDelayType,
 		bootstrapErrReportType, retry.OnRetry(func(n uint, err error) {
 			r.logger.Warnf("Failed to bootstap reporter: %v. Attempt: %d, Max attempts: %d", err, n+1, bootstrapAttempts)
+			if errors.Is(err, types.ErrChainInconsistency) {
+				r.alerts.Inconsistency(journal.ModuleReporter, err)
+			}
+			r.alerts.BootstrapFailed(journal.ModuleReporter, err)
 		})); err != nil {
 
-		if errors.Is(err, context.Canceled) {
+		if errors.Is(err, context.Canceled) || r.ShuttingDown() {
 			// context was cancelled we do not need to anything more, app is quiting
-			return
+			return nil
 		}
 
-		// we failed to bootstrap multiple time, we should panic as something unexpected is happening.
-		r.logger.Fatalf("Failed to bootstrap reporter: %v after %d attempts", err, bootstrapAttempts)
+		r.logger.Errorf("Failed to bootstrap reporter: %v after %d attempts", err, bootstrapAttempts)
+		return fmt.Errorf("failed to bootstrap reporter after %d attempts: %w", bootstrapAttempts, err)
 	}
+	return nil
 }
 
 // initBTCCache fetches the blocks since T-k-w in the BTC canonical chain
@@ -191,22 +205,22 @@ func (r *Reporter) initBTCCache() error {
 		ibs                      []*types.IndexedBlock
 	)
 
-	r.btcCache, err = types.NewBTCCache(r.Cfg.BTCCacheSize) // TODO: give an option to be unsized
+	r.btcCache, err = types.NewBTCCache(r.Cfg.BTCCacheSize)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	// get T, i.e., total block count in Lorenzo header chain
-	tipRes, err := r.lorenzoClient.BTCHeaderChainTip()
+	tipRes, err := r.queryLorenzoTip()
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get Lorenzo BTC tip: %w: %w", types.ErrLorenzoUnavailable, err)
 	}
 	lorenzoLatestBlockHeight = tipRes.Header.Height
 
 	// Find the base height
-	baseRes, err := r.lorenzoClient.BTCBaseHeader()
+	baseRes, err := r.lorenzoClient.BTCBaseHeader(r.quitCtx())
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get Lorenzo BTC base header: %w: %w", types.ErrLorenzoUnavailable, err)
 	}
 	lorenzoBaseHeight = baseRes.Header.Height
 
@@ -220,27 +234,32 @@ func (r *Reporter) initBTCCache() error {
 		baseHeight = lorenzoBaseHeight
 	}
 
-	ibs, err = r.btcClient.FindTailBlocksByHeight(baseHeight)
+	ibs, err = r.btcClient.FindTailBlocksByHeight(r.quitCtx(), baseHeight)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("failed to get BTC blocks since height %d: %w: %w", baseHeight, types.ErrSourceChainUnavailable, err)
 	}
 
 	if err = r.btcCache.Init(ibs); err != nil {
-		panic(err)
+		return err
 	}
+	if r.headerTree, err = types.NewHeaderTree(ibs); err != nil {
+		return err
+	}
+	r.recordBootstrap(baseRes.Header, ibs)
 	return nil
 }
 
 func (r *Reporter) waitLorenzoCatchUpCloseToBTCTip() error {
 	closeGap := r.btcConfirmationDepth * 2
-	_, btcTip, err := r.btcClient.GetBestBlock()
+	_, btcTip, err := r.btcClient.GetBestBlock(r.quitCtx())
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get BTC tip: %w: %w", types.ErrSourceChainUnavailable, err)
 	}
+	r.recordBTCTip(btcTip)
 
-	lorenzoTip, err := r.lorenzoClient.BTCHeaderChainTip()
+	lorenzoTip, err := r.queryLorenzoTip()
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get Lorenzo BTC tip: %w: %w", types.ErrLorenzoUnavailable, err)
 	}
 	if lorenzoTip.Header.Height+closeGap >= btcTip {
 		// don't anything
@@ -251,8 +270,11 @@ func (r *Reporter) waitLorenzoCatchUpCloseToBTCTip() error {
 	quit := r.quitChan()
 	if lorenzoTip.Header.Height+closeGap < btcTip {
 		overCh := make(chan struct{})
-		errorCh := make(chan error)
+		errorCh := make(chan error, 1)
 		ibCh := make(chan []*types.IndexedBlock, 10)
+		// stops the fetching when the catch up returns early
+		done := make(chan struct{})
+		defer close(done)
 		batchSize := uint64(FetchBTCBlocksBatchSize)
 		go func() {
 			for h := lorenzoTip.Header.Height + 1; h < btcTip-closeGap; h++ {
@@ -260,6 +282,8 @@ func (r *Reporter) waitLorenzoCatchUpCloseToBTCTip() error {
 				case <-quit:
 					close(overCh)
 					return
+				case <-done:
+					return
 				default:
 				}
 
@@ -269,14 +293,18 @@ func (r *Reporter) waitLorenzoCatchUpCloseToBTCTip() error {
 				}
 
 				startFetch := time.Now()
-				ibs, err := r.btcClient.FindRangeBlocksByHeight(h, endHeight)
+				ibs, err := r.btcClient.FindRangeBlocksByHeight(r.quitCtx(), h, endHeight)
 				r.logger.Infof("fetch block from %d to %d, time used: %v", h, endHeight, time.Since(startFetch))
 				if err != nil {
-					errorCh <- err
+					errorCh <- fmt.Errorf("failed to get BTC blocks from %d to %d: %w: %w", h, endHeight, types.ErrSourceChainUnavailable, err)
 					return
 				}
 
-				ibCh <- ibs
+				select {
+				case ibCh <- ibs:
+				case <-done:
+					return
+				}
 				h = endHeight
 			}
 
@@ -290,13 +318,16 @@ func (r *Reporter) waitLorenzoCatchUpCloseToBTCTip() error {
 			select {
 			case ibs := <-ibCh:
 				if ibs[0].Header.PrevBlock.IsEqual(lorenzoNewTipHeader) == false {
-					r.logger.Panicf("height(%d) PrevBlock(%s) is not lorenzo tip(%s)",
+					return fmt.Errorf("%w: height(%d) PrevBlock(%s) is not lorenzo tip(%s)", types.ErrChainInconsistency,
 						ibs[0].Height, ibs[0].Header.PrevBlock.String(), lorenzoNewTipHeader.String())
 				}
+				if !r.waitUntilUnpaused() {
+					return nil
+				}
 
-				_, err = r.ProcessHeaders(signer, ibs)
+				_, err = r.ProcessHeaders(audit.WithReason(r.quitCtx(), audit.ReasonCatchUp), signer, ibs)
 				if err != nil {
-					panic(err)
+					return fmt.Errorf("failed to submit headers to catch up with BTC: %w", err)
 				}
 				currentHash := ibs[len(ibs)-1].Header.BlockHash()
 				lorenzoNewTipHeader = &currentHash
@@ -325,16 +356,14 @@ func (r *Reporter) waitUntilBTCSync() error {
 	)
 
 	// Retrieve hash/height of the latest block in BTC
-	btcLatestBlockHash, btcLatestBlockHeight, err = r.btcClient.GetBestBlock()
+	btcLatestBlockHash, btcLatestBlockHeight, err = r.btcClient.GetBestBlock(r.quitCtx())
 	if err != nil {
 		return err
 	}
 	r.logger.Debugf("BTC latest block hash and height: (%v, %d)", btcLatestBlockHash, btcLatestBlockHeight)
 
-	// TODO: if BTC falls behind BTCLightclient's base header, then the lrzrelayer is incorrectly configured and should panic
-
 	// Retrieve hash/height of the latest block in Lorenzo header chain
-	tipRes, err := r.lorenzoClient.BTCHeaderChainTip()
+	tipRes, err := r.queryLorenzoTip()
 	if err != nil {
 		return err
 	}
@@ -350,11 +379,11 @@ func (r *Reporter) waitUntilBTCSync() error {
 		// When BTC catches up, break and continue the bootstrapping process
 		ticker := time.NewTicker(5 * time.Second)
 		for range ticker.C {
-			_, btcLatestBlockHeight, err = r.btcClient.GetBestBlock()
+			_, btcLatestBlockHeight, err = r.btcClient.GetBestBlock(r.quitCtx())
 			if err != nil {
 				return err
 			}
-			tipRes, err = r.lorenzoClient.BTCHeaderChainTip()
+			tipRes, err = r.queryLorenzoTip()
 			if err != nil {
 				return err
 			}
@@ -371,12 +400,10 @@ func (r *Reporter) waitUntilBTCSync() error {
 }
 
 func (r *Reporter) checkHeaderConsistency(consistencyCheckHeight uint64) error {
-	var err error
-
 	consistencyCheckBlock := r.btcCache.FindBlock(consistencyCheckHeight)
 	if consistencyCheckBlock == nil {
-		err = fmt.Errorf("cannot find the %d-th block of Lorenzo header chain in BTC cache for initial consistency check", consistencyCheckHeight)
-		panic(err)
+		return fmt.Errorf("%w: cannot find the %d-th block of Lorenzo header chain in BTC cache for initial consistency check",
+			types.ErrChainInconsistency, consistencyCheckHeight)
 	}
 	consistencyCheckHash := consistencyCheckBlock.BlockHash()
 
@@ -386,13 +413,13 @@ func (r *Reporter) checkHeaderConsistency(consistencyCheckHeight uint64) error {
 	// generating a header that can be in two different positions in two different BTC header chains
 	// is as hard as breaking the hash function.
 	// So as long as the block exists on Lorenzo, it has to be at the same position as in Lorenzo as well.
-	res, err := r.lorenzoClient.ContainsBTCBlock(&consistencyCheckHash) // TODO: this API has error. Find out why
+	res, err := r.lorenzoClient.ContainsBTCBlock(r.quitCtx(), &consistencyCheckHash) // TODO: this API has error. Find out why
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to check whether Lorenzo contains BTC block %v: %w: %w", consistencyCheckHash, types.ErrLorenzoUnavailable, err)
 	}
 	if !res.Contains {
-		err = fmt.Errorf("BTC main chain is inconsistent with Lorenzo header chain: k-deep block in Lorenzo header chain: %v", consistencyCheckHash)
-		panic(err)
+		return fmt.Errorf("%w: BTC main chain is inconsistent with Lorenzo header chain: k-deep block in Lorenzo header chain: %v",
+			types.ErrChainInconsistency, consistencyCheckHash)
 	}
 	return nil
 }
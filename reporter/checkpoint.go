@@ -0,0 +1,88 @@
+package reporter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// checkpoints maps BTC heights to the block hashes trusted at those heights
+type checkpoints map[uint64]chainhash.Hash
+
+func newCheckpoints(cfgs []config.CheckpointConfig) (checkpoints, error) {
+	cps := make(checkpoints, len(cfgs))
+	for _, cp := range cfgs {
+		hash, err := chainhash.NewHashFromStr(cp.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid checkpoint hash at height %d: %w", cp.Height, err)
+		}
+		cps[cp.Height] = *hash
+	}
+	return cps, nil
+}
+
+// heights returns the checkpoint heights in ascending order
+func (cps checkpoints) heights() []uint64 {
+	heights := make([]uint64, 0, len(cps))
+	for h := range cps {
+		heights = append(heights, h)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights
+}
+
+// check returns an error if the given block hash contradicts the checkpoint at the given height
+func (cps checkpoints) check(height uint64, hash *chainhash.Hash) error {
+	expected, ok := cps[height]
+	if !ok || expected.IsEqual(hash) {
+		return nil
+	}
+	return fmt.Errorf("%w: BTC block at height %d is %s, expected %s", types.ErrCheckpointMismatch, height, hash, &expected)
+}
+
+// verifyCheckpoints ensures that the best chain of the BTC node agrees with every checkpoint it has reached.
+// Checkpoints above the BTC tip are checked later, when the corresponding blocks get relayed.
+func (r *Reporter) verifyCheckpoints() error {
+	if len(r.checkpoints) == 0 {
+		return nil
+	}
+
+	_, btcTipHeight, err := r.btcClient.GetBestBlock(r.quitCtx())
+	if err != nil {
+		return err
+	}
+
+	for _, height := range r.checkpoints.heights() {
+		if height > btcTipHeight {
+			break
+		}
+		hash, err := r.btcClient.GetBlockHash(r.quitCtx(), int64(height))
+		if err != nil {
+			return fmt.Errorf("failed to get BTC block hash at checkpoint height %d: %w: %w", height, types.ErrSourceChainUnavailable, err)
+		}
+		if err := r.checkpoints.check(height, hash); err != nil {
+			return err
+		}
+	}
+
+	r.logger.Debugf("BTC node agrees with %d checkpoints", len(r.checkpoints))
+	return nil
+}
+
+// checkBlocksAgainstCheckpoints ensures none of the given blocks contradicts a checkpoint
+func (r *Reporter) checkBlocksAgainstCheckpoints(ibs []*types.IndexedBlock) error {
+	if len(r.checkpoints) == 0 {
+		return nil
+	}
+	for _, ib := range ibs {
+		hash := ib.BlockHash()
+		if err := r.checkpoints.check(uint64(ib.Height), &hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
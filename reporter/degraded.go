@@ -0,0 +1,99 @@
+package reporter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/audit"
+)
+
+// degradedState describes an ongoing Lorenzo outage. While degraded, the reporter keeps
+// buffering BTC blocks in its cache and probes Lorenzo with backoff instead of bootstrapping.
+// It is only used by the goroutine handling block events.
+type degradedState struct {
+	since   time.Time
+	backoff time.Duration
+}
+
+// enterDegraded switches to degraded mode after Lorenzo could not be reached
+func (r *Reporter) enterDegraded(err error) {
+	if r.degraded != nil {
+		return
+	}
+	r.logger.Warnf("Lorenzo is unreachable: %v. Buffering BTC blocks until it is back", err)
+	r.degraded = &degradedState{
+		since:   time.Now(),
+		backoff: r.retrySleepTime,
+	}
+
+	// recovery submits everything Lorenzo misses from the cache, so it must outlast the outage
+	if err := r.btcCache.Resize(r.Cfg.BTCCacheSize); err != nil {
+		r.logger.Errorf("Failed to resize BTC cache: %v", err)
+	}
+	if pending := r.submitQueue.take(); len(pending) > 0 {
+		r.logger.Debugf("Dropped %d pending headers, they will be submitted from the cache once Lorenzo is back", len(pending))
+	}
+}
+
+// degradedProbeTimer fires when it is time to probe Lorenzo again. It returns nil if Lorenzo is reachable.
+func (r *Reporter) degradedProbeTimer() <-chan time.Time {
+	if r.degraded == nil {
+		return nil
+	}
+	return time.After(r.degraded.backoff)
+}
+
+// probeLorenzo checks whether Lorenzo is reachable again, and if so submits the headers buffered
+// during the outage. It returns an error if the cache no longer connects to Lorenzo's tip and bootstrap is required.
+func (r *Reporter) probeLorenzo() error {
+	lorenzoTip, err := r.queryLorenzoTip()
+	if err != nil {
+		r.degraded.backoff *= 2
+		if r.degraded.backoff > r.maxRetrySleepTime {
+			r.degraded.backoff = r.maxRetrySleepTime
+		}
+		r.logger.Debugf("Lorenzo is still unreachable: %v. Next probe in %v", err, r.degraded.backoff)
+		return nil
+	}
+
+	r.logger.Infof("Lorenzo is reachable again after %v, submitting buffered BTC blocks", time.Since(r.degraded.since))
+	r.degraded = nil
+
+	cacheFirst, cacheTip := r.btcCache.First(), r.btcCache.Tip()
+	if cacheFirst == nil {
+		return fmt.Errorf("cache is empty, restart bootstrap process")
+	}
+	if lorenzoTip.Header.Height+1 < uint64(cacheFirst.Height) {
+		return fmt.Errorf("cache (first %d) no longer connects to Lorenzo tip %d, restart bootstrap process",
+			cacheFirst.Height, lorenzoTip.Header.Height)
+	}
+
+	if lorenzoTip.Header.Height < uint64(cacheTip.Height) {
+		// if Lorenzo's tip is not on our chain, a reorg happened during the outage,
+		// and submission resumes from the first cached block Lorenzo misses
+		startHeight := lorenzoTip.Header.Height + 1
+		if r.btcCache.FindBlockByHash(lorenzoTip.Header.Hash.ToChainhash()) == nil {
+			startHeight = uint64(cacheFirst.Height)
+		}
+		ibs, err := r.btcCache.GetLastBlocks(startHeight)
+		if err != nil {
+			return err
+		}
+		r.submitQueue.take()
+		ctx := audit.WithReason(r.quitCtx(), audit.ReasonBackfill)
+		if err := r.enqueueHeaders(ctx, ibs); err != nil {
+			return err
+		}
+		r.flushSubmitQueue(ctx)
+	}
+
+	// Lorenzo may have gone away again while we were submitting
+	if r.degraded == nil {
+		maxEntries := r.btcConfirmationDepth + r.checkpointFinalizationTimeout
+		if err := r.btcCache.Resize(maxEntries); err != nil {
+			r.logger.Errorf("Failed to resize BTC cache: %v", err)
+		}
+		r.btcCache.Trim()
+	}
+	return nil
+}
@@ -9,11 +9,17 @@ import (
 	pv "github.com/cosmos/relayer/v2/relayer/provider"
 )
 
+// LorenzoClient queries Lorenzo and sends its txs. Queries give up once the given context is done or the
+// timeout of the Lorenzo config passes.
 type LorenzoClient interface {
 	MustGetAddr() string
+	// Paused returns whether broadcasting is paused because the signers ran out of funds
+	Paused() bool
 	GetConfig() *config.LorenzoConfig
-	InsertHeaders(ctx context.Context, msgs *btclctypes.MsgInsertHeaders) (*pv.RelayerTxResponse, error)
-	ContainsBTCBlock(blockHash *chainhash.Hash) (*btclctypes.QueryContainsBytesResponse, error)
-	BTCHeaderChainTip() (*btclctypes.QueryTipResponse, error)
-	BTCBaseHeader() (*btclctypes.QueryBaseHeaderResponse, error)
+	// InsertHeaders returns the address of the key that signed the tx, which replaces msgs.Signer
+	InsertHeaders(ctx context.Context, msgs *btclctypes.MsgInsertHeaders) (*pv.RelayerTxResponse, string, error)
+	TxGasUsed(ctx context.Context, txHash string) (int64, error)
+	ContainsBTCBlock(ctx context.Context, blockHash *chainhash.Hash) (*btclctypes.QueryContainsBytesResponse, error)
+	BTCHeaderChainTip(ctx context.Context) (*btclctypes.QueryTipResponse, error)
+	BTCBaseHeader(ctx context.Context) (*btclctypes.QueryBaseHeaderResponse, error)
 }
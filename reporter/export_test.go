@@ -0,0 +1,4 @@
+package reporter
+
+// RecordSpans records the spans started until the end of the test
+var RecordSpans = recordSpans
@@ -0,0 +1,55 @@
+package reporter
+
+import (
+	btclctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// queryLorenzoTip queries the tip of Lorenzo's BTC light client and journals the response
+func (r *Reporter) queryLorenzoTip() (*btclctypes.QueryTipResponse, error) {
+	res, err := r.lorenzoClient.BTCHeaderChainTip(r.quitCtx())
+	r.journal.RecordBTCTip(res, err)
+	if err == nil {
+		r.recordLorenzoTip(res.Header.Height)
+	}
+	return res, err
+}
+
+// recordBootstrap journals the Lorenzo base header and the blocks bootstrap loaded into the BTC cache
+func (r *Reporter) recordBootstrap(base *btclctypes.BTCHeaderInfo, ibs []*types.IndexedBlock) {
+	if r.journal == nil {
+		return
+	}
+	blocks := make([]journal.Header, 0, len(ibs))
+	for _, ib := range ibs {
+		blocks = append(blocks, journal.NewHeader(ib.Height, ib.Header))
+	}
+	r.journal.Record(journal.ModuleReporter, journal.KindBootstrap, &journal.Bootstrap{
+		BaseHeight: base.Height,
+		BaseHash:   base.Hash.MarshalHex(),
+		Blocks:     blocks,
+	})
+}
+
+// recordSubmission journals which of the candidate blocks were submitted to Lorenzo, given the msgs that succeeded
+func (r *Reporter) recordSubmission(ibs []*types.IndexedBlock, submitted []*btclctypes.MsgInsertHeaders, err error) {
+	if r.journal == nil || len(ibs) == 0 {
+		return
+	}
+	submission := &journal.Submission{
+		FirstHeight: uint64(ibs[0].Height),
+		LastHeight:  uint64(ibs[len(ibs)-1].Height),
+		Submitted:   []string{},
+	}
+	for _, msg := range submitted {
+		for _, header := range msg.Headers {
+			submission.Submitted = append(submission.Submitted, header.Hash().MarshalHex())
+		}
+	}
+	if err != nil {
+		submission.Error = err.Error()
+	}
+	r.journal.Record(journal.ModuleReporter, journal.KindSubmission, submission)
+}
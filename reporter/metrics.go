@@ -0,0 +1,99 @@
+package reporter
+
+import (
+	"time"
+
+	btclctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+const (
+	// metricsInterval is how often the time-based metrics are updated
+	metricsInterval = time.Second
+	// maxFirstSeen bounds the blocks awaiting inclusion whose first sighting is remembered
+	maxFirstSeen = 10000
+	// firstSeenTTL is how long a block that never gets included, e.g., one reorged out, is remembered
+	firstSeenTTL = 24 * time.Hour
+)
+
+// metricsLoop updates the time-based metrics until the reporter stops
+func (r *Reporter) metricsLoop() {
+	defer r.wg.Done()
+	quit := r.quitChan()
+
+	ticker := time.NewTicker(metricsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.metrics.UpdateSinceLastHeader()
+		case <-quit:
+			return
+		}
+	}
+}
+
+// recordBTCTip updates the BTC tip and lag metrics
+func (r *Reporter) recordBTCTip(height uint64) {
+	r.btcTip.Store(height)
+	r.metrics.BTCTipGauge.Set(float64(height))
+	r.recordLag()
+}
+
+// recordLorenzoTip updates the Lorenzo tip and lag metrics
+func (r *Reporter) recordLorenzoTip(height uint64) {
+	r.lorenzoTip.Store(height)
+	r.metrics.LorenzoTipGauge.Set(float64(height))
+	r.recordLag()
+}
+
+func (r *Reporter) recordLag() {
+	btcTip, lorenzoTip := r.btcTip.Load(), r.lorenzoTip.Load()
+	if btcTip == 0 || lorenzoTip == 0 {
+		return
+	}
+	r.metrics.LagBlocksGauge.Set(float64(btcTip) - float64(lorenzoTip))
+}
+
+// recordFirstSeen remembers when the block event of a block was first received
+func (r *Reporter) recordFirstSeen(hash chainhash.Hash) {
+	now := time.Now()
+	r.firstSeenMu.Lock()
+	defer r.firstSeenMu.Unlock()
+	if _, ok := r.firstSeen[hash]; ok {
+		return
+	}
+	if len(r.firstSeen) >= maxFirstSeen {
+		for h, seen := range r.firstSeen {
+			if now.Sub(seen) > firstSeenTTL {
+				delete(r.firstSeen, h)
+			}
+		}
+		if len(r.firstSeen) >= maxFirstSeen {
+			return
+		}
+	}
+	r.firstSeen[hash] = now
+}
+
+// recordInclusion updates the metrics of a tx included in Lorenzo with the given msg, whose last header is at lastHeight
+func (r *Reporter) recordInclusion(msg *btclctypes.MsgInsertHeaders, lastHeight uint64) {
+	r.metrics.SuccessfulHeadersCounter.Add(float64(len(msg.Headers)))
+	r.metrics.HeadersPerTxHistogram.Observe(float64(len(msg.Headers)))
+	r.metrics.HeadersReported()
+	// the tip may be further ahead if another relayer submitted the headers as well
+	if lastHeight > r.lorenzoTip.Load() {
+		r.recordLorenzoTip(lastHeight)
+	}
+
+	now := time.Now()
+	r.firstSeenMu.Lock()
+	defer r.firstSeenMu.Unlock()
+	for _, header := range msg.Headers {
+		hash := header.Hash().ToChainhash()
+		if seen, ok := r.firstSeen[*hash]; ok {
+			r.metrics.InclusionDelayHistogram.Observe(now.Sub(seen).Seconds())
+			delete(r.firstSeen, *hash)
+		}
+	}
+}
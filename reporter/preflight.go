@@ -0,0 +1,85 @@
+package reporter
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/netparams"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+const (
+	// SyncCheckInterval is how often a reporter waiting for the BTC node to finish initial block download checks it again
+	SyncCheckInterval = 30 * time.Second
+	// maxBlocksBehindHeaders is how far the node's blocks may lag its headers before it is considered syncing
+	maxBlocksBehindHeaders = 6
+)
+
+// preflight ensures the BTC node is on the configured network, that Lorenzo's base header is on the node's chain,
+// and waits until the node finished initial block download. It returns an error if the node, the config and
+// Lorenzo do not agree, as relaying would only produce headers Lorenzo rejects.
+func (r *Reporter) preflight() error {
+	if !r.waitUntilBTCNodeSynced() {
+		return nil
+	}
+
+	info, err := r.btcClient.GetBlockChainInfo(r.quitCtx())
+	if err != nil {
+		return fmt.Errorf("failed to get BTC chain info: %w: %w", types.ErrSourceChainUnavailable, err)
+	}
+	if names := netparams.ChainNames(r.btcParams); names != nil && !slices.Contains(names, info.Chain) {
+		return fmt.Errorf("%w: BTC node is on chain %s, but net params %s expect one of %v", types.ErrConfig, info.Chain, r.btcParams.Name, names)
+	}
+
+	genesis, err := r.btcClient.GetBlockHash(r.quitCtx(), 0)
+	if err != nil {
+		return fmt.Errorf("failed to get BTC genesis block hash: %w: %w", types.ErrSourceChainUnavailable, err)
+	}
+	if !genesis.IsEqual(r.btcParams.GenesisHash) {
+		return fmt.Errorf("%w: BTC node has genesis block %s, but net params %s expect %s", types.ErrConfig, genesis, r.btcParams.Name, r.btcParams.GenesisHash)
+	}
+
+	baseRes, err := r.lorenzoClient.BTCBaseHeader(r.quitCtx())
+	if err != nil {
+		return fmt.Errorf("failed to get Lorenzo BTC base header: %w: %w", types.ErrLorenzoUnavailable, err)
+	}
+	if baseRes.Header.Height > uint64(info.Blocks) {
+		return fmt.Errorf("%w: Lorenzo BTC base header %d is above the BTC node tip %d", types.ErrChainInconsistency, baseRes.Header.Height, info.Blocks)
+	}
+	baseHash, err := r.btcClient.GetBlockHash(r.quitCtx(), int64(baseRes.Header.Height))
+	if err != nil {
+		return fmt.Errorf("failed to get BTC block hash at Lorenzo base height %d: %w: %w", baseRes.Header.Height, types.ErrSourceChainUnavailable, err)
+	}
+	if !baseHash.IsEqual(baseRes.Header.Hash.ToChainhash()) {
+		return fmt.Errorf("%w: Lorenzo BTC base header %s at height %d is not on the BTC node's chain, which has %s",
+			types.ErrChainInconsistency, baseRes.Header.Hash.MarshalHex(), baseRes.Header.Height, baseHash)
+	}
+
+	r.logger.Infof("BTC node is on chain %s with genesis %s and Lorenzo base header %d", info.Chain, genesis, baseRes.Header.Height)
+	return nil
+}
+
+// waitUntilBTCNodeSynced blocks while the BTC node is in initial block download, so that the reporter never relays
+// a chain the node has not fully validated yet. It returns false if the reporter is stopped while waiting.
+func (r *Reporter) waitUntilBTCNodeSynced() bool {
+	quit := r.quitChan()
+	for {
+		info, err := r.btcClient.GetBlockChainInfo(r.quitCtx())
+		switch {
+		case err != nil:
+			r.logger.Warnf("Failed to get BTC chain info: %v", err)
+		case info.InitialBlockDownload || info.Headers-info.Blocks > maxBlocksBehindHeaders:
+			r.logger.Infof("BTC node is syncing (blocks: %d, headers: %d, progress: %.2f%%), waiting before relaying",
+				info.Blocks, info.Headers, info.VerificationProgress*100)
+		default:
+			return true
+		}
+
+		select {
+		case <-quit:
+			return false
+		case <-time.After(SyncCheckInterval):
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package reporter
+
+import (
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
+// UpdateConfig applies the settings of the given config that can change at runtime: delay blocks, maximum
+// headers in a message, the submit batch window and the stall threshold of the watchdog. It is safe to call
+// from any goroutine; the block event handler applies the latest update once it is done with the current event.
+func (r *Reporter) UpdateConfig(cfg *config.ReporterConfig) {
+	// replaces an update the handler has not picked up yet
+	r.pendingCfg.Store(cfg)
+	select {
+	case r.reloadChan <- struct{}{}:
+	default:
+		// the handler is already signaled
+	}
+}
+
+// applyPendingConfig applies the latest config update, if any, only called by the block event handler
+func (r *Reporter) applyPendingConfig() {
+	if cfg := r.pendingCfg.Swap(nil); cfg != nil {
+		r.applyConfig(cfg)
+	}
+}
+
+// applyConfig applies a config update, only called by the block event handler
+func (r *Reporter) applyConfig(cfg *config.ReporterConfig) {
+	updated := *r.Cfg
+	updated.DelayBlocks = cfg.DelayBlocks
+	updated.MaxHeadersInMsg = cfg.MaxHeadersInMsg
+	updated.SubmitBatchWindow = cfg.SubmitBatchWindow
+	updated.StallAfter = cfg.StallAfter
+	r.Cfg = &updated
+
+	r.delayBlocks = cfg.DelayBlocks
+	r.submitQueue.window = cfg.SubmitBatchWindow
+	r.submitQueue.maxSize = int(cfg.MaxHeadersInMsg)
+	r.logger.Infof("Applied config update. delay blocks: %d, max headers in msg: %d, submit batch window: %v, stall after: %v",
+		cfg.DelayBlocks, cfg.MaxHeadersInMsg, cfg.SubmitBatchWindow, cfg.StallAfter)
+}
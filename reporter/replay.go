@@ -0,0 +1,248 @@
+package reporter
+
+import (
+	"fmt"
+	"time"
+
+	lorenzotypes "github.com/Lorenzo-Protocol/lorenzo/v3/types"
+	btclctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// retries are pointless against the replay clients, so keep them short
+const replayRetrySleepTime = time.Millisecond
+
+// ReplayDivergence is a journaled block event whose replay did not reproduce the journaled outcome
+type ReplayDivergence struct {
+	Time      time.Time
+	EventType types.EventType
+	Height    int32
+	Hash      string
+	// Recorded and Replayed list the hashes of the headers submitted to Lorenzo
+	Recorded []string
+	Replayed []string
+	// Error is set if the replayed event required a bootstrap the journal does not show
+	Error string
+}
+
+// ReplayResult summarises a replay
+type ReplayResult struct {
+	Bootstraps  int
+	BlockEvents int
+	// Skipped counts records of other modules and records preceding the first bootstrap
+	Skipped     int
+	Divergences []*ReplayDivergence
+}
+
+// replayStep is a bootstrap or block event record together with the tip queries
+// and submissions journaled while it was handled
+type replayStep struct {
+	record      *journal.Record
+	tips        []journal.Tip
+	submissions []journal.Submission
+}
+
+// failed returns whether a submission of the step failed
+func (s *replayStep) failed() bool {
+	for _, submission := range s.submissions {
+		if submission.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *replayStep) submitted() []string {
+	submitted := []string{}
+	for _, submission := range s.submissions {
+		submitted = append(submitted, submission.Submitted...)
+	}
+	return submitted
+}
+
+// Replay feeds journaled records back through the reporter's block event handling, with clients
+// that reproduce the BTC chain and the Lorenzo responses seen in production. Every bootstrap record
+// resets the replayed state, and every block event is handled as the reporter would have handled it.
+func Replay(cfg *config.ReporterConfig, parentLogger *zap.Logger, records []*journal.Record) (*ReplayResult, error) {
+	result := &ReplayResult{}
+	steps := groupReplaySteps(records, result)
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("journal has no reporter bootstrap record to start from")
+	}
+
+	btcClient := newReplayBTCClient()
+	lorenzoClient := newReplayLorenzoClient()
+	r, err := New(cfg, parentLogger, btcClient, lorenzoClient, replayRetrySleepTime, replayRetrySleepTime, metrics.NewReporterMetrics(), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.delayBlocks = 0
+
+	for i, step := range steps {
+		if step.record.Kind == journal.KindBootstrap {
+			if err := r.replayBootstrap(step, btcClient, lorenzoClient); err != nil {
+				return nil, fmt.Errorf("failed to replay bootstrap at %v: %w", step.record.Time, err)
+			}
+			result.Bootstraps++
+			continue
+		}
+
+		var event journal.BlockEvent
+		if err := step.record.Decode(&event); err != nil {
+			return nil, fmt.Errorf("invalid block event at %v: %w", step.record.Time, err)
+		}
+		ib, err := event.IndexedBlock()
+		if err != nil {
+			return nil, fmt.Errorf("invalid block event at %v: %w", step.record.Time, err)
+		}
+		result.BlockEvents++
+
+		switch event.EventType {
+		case types.BlockConnected:
+			btcClient.connect(ib)
+		case types.BlockDisconnected:
+			btcClient.disconnect()
+		}
+		lorenzoClient.tips = step.tips
+		lorenzoClient.submitted = []string{}
+		// reproduce the failure of a journaled submission once the headers accepted before it are submitted
+		lorenzoClient.accepted = -1
+		if step.failed() {
+			lorenzoClient.accepted = len(step.submitted())
+		}
+
+		handleErr := r.handleBlockEvent(r.quitCtx(), types.NewBlockEvent(event.EventType, ib.Height, ib.Header))
+		// the journal shows a batch submitted before the next event, i.e. its window closed
+		if len(lorenzoClient.submitted) < len(step.submitted()) {
+			r.flushSubmitQueue(r.quitCtx())
+		}
+
+		// an event that required a bootstrap is expected to be followed by a bootstrap record
+		bootstrapped := i+1 < len(steps) && steps[i+1].record.Kind == journal.KindBootstrap
+		recorded := step.submitted()
+		if !equalHashes(recorded, lorenzoClient.submitted) || (handleErr != nil && !bootstrapped) {
+			divergence := &ReplayDivergence{
+				Time:      step.record.Time,
+				EventType: event.EventType,
+				Height:    ib.Height,
+				Hash:      ib.BlockHash().String(),
+				Recorded:  recorded,
+				Replayed:  lorenzoClient.submitted,
+			}
+			if handleErr != nil {
+				divergence.Error = handleErr.Error()
+			}
+			result.Divergences = append(result.Divergences, divergence)
+		}
+	}
+
+	return result, nil
+}
+
+// replayBootstrap resets the replayed BTC chain, light client and reporter state to a journaled bootstrap
+func (r *Reporter) replayBootstrap(step *replayStep, btcClient *replayBTCClient, lorenzoClient *replayLorenzoClient) error {
+	var bootstrap journal.Bootstrap
+	if err := step.record.Decode(&bootstrap); err != nil {
+		return err
+	}
+	ibs := make([]*types.IndexedBlock, 0, len(bootstrap.Blocks))
+	for i := range bootstrap.Blocks {
+		ib, err := bootstrap.Blocks[i].IndexedBlock()
+		if err != nil {
+			return err
+		}
+		ibs = append(ibs, ib)
+	}
+	btcClient.reset(ibs)
+
+	// the light client holds the base header, the cached blocks up to the tip bootstrap
+	// saw when checking consistency, and the headers bootstrap then submitted
+	baseHash, err := lorenzotypes.NewBTCHeaderHashBytesFromHex(bootstrap.BaseHash)
+	if err != nil {
+		return err
+	}
+	lorenzoClient.base = &btclctypes.BTCHeaderInfo{Hash: &baseHash, Height: bootstrap.BaseHeight}
+	lorenzoClient.known = map[chainhash.Hash]uint64{*baseHash.ToChainhash(): bootstrap.BaseHeight}
+	lorenzoTip := bootstrap.BaseHeight
+	if len(step.tips) > 0 && step.tips[0].Error == "" {
+		lorenzoTip = step.tips[0].Height
+	}
+	for _, ib := range ibs {
+		if uint64(ib.Height) <= lorenzoTip {
+			lorenzoClient.insert(ib.BlockHash(), uint64(ib.Height))
+		}
+	}
+	for _, submitted := range step.submitted() {
+		hash, err := chainhash.NewHashFromStr(submitted)
+		if err != nil {
+			return err
+		}
+		if ib, ok := btcClient.blocks[*hash]; ok {
+			lorenzoClient.insert(*hash, uint64(ib.Height))
+		}
+	}
+
+	r.btcCache, err = types.NewBTCCache(r.Cfg.BTCCacheSize)
+	if err != nil {
+		return err
+	}
+	if r.headerTree, err = types.NewHeaderTree(ibs); err != nil {
+		return err
+	}
+	return r.btcCache.Init(ibs)
+}
+
+// groupReplaySteps splits the reporter's records into steps, starting from the first bootstrap
+func groupReplaySteps(records []*journal.Record, result *ReplayResult) []*replayStep {
+	var steps []*replayStep
+	for _, record := range records {
+		if record.Module != journal.ModuleReporter {
+			result.Skipped++
+			continue
+		}
+
+		switch record.Kind {
+		case journal.KindBootstrap, journal.KindBlockEvent:
+			if len(steps) == 0 && record.Kind != journal.KindBootstrap {
+				result.Skipped++
+				continue
+			}
+			steps = append(steps, &replayStep{record: record})
+		case journal.KindTip:
+			var tip journal.Tip
+			if len(steps) == 0 || record.Decode(&tip) != nil {
+				result.Skipped++
+				continue
+			}
+			steps[len(steps)-1].tips = append(steps[len(steps)-1].tips, tip)
+		case journal.KindSubmission:
+			var submission journal.Submission
+			if len(steps) == 0 || record.Decode(&submission) != nil {
+				result.Skipped++
+				continue
+			}
+			steps[len(steps)-1].submissions = append(steps[len(steps)-1].submissions, submission)
+		default:
+			result.Skipped++
+		}
+	}
+	return steps
+}
+
+func equalHashes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
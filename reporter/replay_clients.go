@@ -0,0 +1,237 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	lrzcfg "github.com/Lorenzo-Protocol/lorenzo-sdk/v3/config"
+	lorenzotypes "github.com/Lorenzo-Protocol/lorenzo/v3/types"
+	btclctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/btcclient"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+const replaySigner = "replay"
+
+var (
+	errNotReplayed      = errors.New("not available during replay")
+	errJournaledFailure = errors.New("the journaled submission failed")
+)
+
+// replayBTCClient is a BTC client whose best chain is rebuilt from journaled bootstraps and block events
+type replayBTCClient struct {
+	best   map[int32]*types.IndexedBlock
+	tip    int32
+	blocks map[chainhash.Hash]*types.IndexedBlock
+}
+
+var _ btcclient.BTCClient = (*replayBTCClient)(nil)
+
+func newReplayBTCClient() *replayBTCClient {
+	return &replayBTCClient{
+		best:   make(map[int32]*types.IndexedBlock),
+		blocks: make(map[chainhash.Hash]*types.IndexedBlock),
+	}
+}
+
+// reset replaces the best chain with the given blocks, sorted by height
+func (c *replayBTCClient) reset(ibs []*types.IndexedBlock) {
+	c.best = make(map[int32]*types.IndexedBlock, len(ibs))
+	c.tip = 0
+	for _, ib := range ibs {
+		c.connect(ib)
+	}
+}
+
+// connect makes ib the tip of the best chain, dropping any blocks above it
+func (c *replayBTCClient) connect(ib *types.IndexedBlock) {
+	for h := ib.Height + 1; h <= c.tip; h++ {
+		delete(c.best, h)
+	}
+	c.best[ib.Height] = ib
+	c.blocks[ib.BlockHash()] = ib
+	c.tip = ib.Height
+}
+
+// disconnect removes the tip of the best chain
+func (c *replayBTCClient) disconnect() {
+	delete(c.best, c.tip)
+	c.tip--
+}
+
+func (c *replayBTCClient) Stop()                                    {}
+func (c *replayBTCClient) WaitForShutdown()                         {}
+func (c *replayBTCClient) MustSubscribeBlocks()                     {}
+func (c *replayBTCClient) ResubscribeBlocks() error                 { return nil }
+func (c *replayBTCClient) BlockEventChan() <-chan *types.BlockEvent { return nil }
+
+func (c *replayBTCClient) GetBestBlock(context.Context) (*chainhash.Hash, uint64, error) {
+	ib, ok := c.best[c.tip]
+	if !ok {
+		return nil, 0, fmt.Errorf("replayed BTC chain is empty")
+	}
+	hash := ib.BlockHash()
+	return &hash, uint64(ib.Height), nil
+}
+
+func (c *replayBTCClient) GetBlockChainInfo(context.Context) (*btcjson.GetBlockChainInfoResult, error) {
+	return nil, errNotReplayed
+}
+
+func (c *replayBTCClient) GetBlockHash(ctx context.Context, blockHeight int64) (*chainhash.Hash, error) {
+	ib, _, err := c.GetBlockByHeight(ctx, uint64(blockHeight))
+	if err != nil {
+		return nil, err
+	}
+	hash := ib.BlockHash()
+	return &hash, nil
+}
+
+func (c *replayBTCClient) GetBlockByHash(_ context.Context, blockHash *chainhash.Hash) (*types.IndexedBlock, *wire.MsgBlock, error) {
+	ib, ok := c.blocks[*blockHash]
+	if !ok {
+		return nil, nil, fmt.Errorf("block %s is not in the journal", blockHash)
+	}
+	return ib, &wire.MsgBlock{Header: *ib.Header}, nil
+}
+
+func (c *replayBTCClient) GetBlockByHeight(_ context.Context, height uint64) (*types.IndexedBlock, *wire.MsgBlock, error) {
+	ib, ok := c.best[int32(height)]
+	if !ok {
+		return nil, nil, fmt.Errorf("block at height %d is not in the journal", height)
+	}
+	return ib, &wire.MsgBlock{Header: *ib.Header}, nil
+}
+
+func (c *replayBTCClient) FindTailBlocksByHeight(ctx context.Context, height uint64) ([]*types.IndexedBlock, error) {
+	return c.FindRangeBlocksByHeight(ctx, height, uint64(c.tip))
+}
+
+func (c *replayBTCClient) FindRangeBlocksByHeight(ctx context.Context, startHeight, endHeight uint64) ([]*types.IndexedBlock, error) {
+	ibs := make([]*types.IndexedBlock, 0, endHeight-startHeight+1)
+	for h := startHeight; h <= endHeight; h++ {
+		ib, _, err := c.GetBlockByHeight(ctx, h)
+		if err != nil {
+			return nil, err
+		}
+		ibs = append(ibs, ib)
+	}
+	return ibs, nil
+}
+
+func (c *replayBTCClient) GetTxOut(context.Context, *chainhash.Hash, uint32, bool) (*btcjson.GetTxOutResult, error) {
+	return nil, errNotReplayed
+}
+
+func (c *replayBTCClient) SendRawTransaction(context.Context, *wire.MsgTx, bool) (*chainhash.Hash, error) {
+	return nil, errNotReplayed
+}
+
+func (c *replayBTCClient) GetTransaction(context.Context, *chainhash.Hash) (*btcjson.GetTransactionResult, error) {
+	return nil, errNotReplayed
+}
+
+func (c *replayBTCClient) GetRawTransaction(context.Context, *chainhash.Hash) (*btcutil.Tx, error) {
+	return nil, errNotReplayed
+}
+
+// replayLorenzoClient simulates Lorenzo's BTC light client. Tip queries are answered with the journaled
+// responses while they last, and inserted headers are captured so they can be compared with the journal.
+type replayLorenzoClient struct {
+	known     map[chainhash.Hash]uint64
+	base      *btclctypes.BTCHeaderInfo
+	tips      []journal.Tip
+	submitted []string
+	// accepted is how many headers Lorenzo accepted before the journaled submission failed, negative if none failed
+	accepted int
+}
+
+var _ LorenzoClient = (*replayLorenzoClient)(nil)
+
+func newReplayLorenzoClient() *replayLorenzoClient {
+	return &replayLorenzoClient{known: make(map[chainhash.Hash]uint64), accepted: -1}
+}
+
+func (c *replayLorenzoClient) insert(hash chainhash.Hash, height uint64) {
+	c.known[hash] = height
+}
+
+func (c *replayLorenzoClient) MustGetAddr() string {
+	return replaySigner
+}
+
+func (c *replayLorenzoClient) Paused() bool {
+	return false
+}
+
+func (c *replayLorenzoClient) GetConfig() *lrzcfg.LorenzoConfig {
+	return &lrzcfg.LorenzoConfig{}
+}
+
+func (c *replayLorenzoClient) InsertHeaders(_ context.Context, msgs *btclctypes.MsgInsertHeaders) (*pv.RelayerTxResponse, string, error) {
+	if c.accepted >= 0 && len(c.submitted)+len(msgs.Headers) > c.accepted {
+		return nil, replaySigner, errJournaledFailure
+	}
+	for _, header := range msgs.Headers {
+		hash := header.Hash()
+		parentHeight, ok := c.known[*header.ParentHash().ToChainhash()]
+		if !ok {
+			return nil, replaySigner, fmt.Errorf("header %s does not extend the replayed light client", hash.MarshalHex())
+		}
+		c.insert(*hash.ToChainhash(), parentHeight+1)
+		c.submitted = append(c.submitted, hash.MarshalHex())
+	}
+	return &pv.RelayerTxResponse{}, replaySigner, nil
+}
+
+func (c *replayLorenzoClient) TxGasUsed(context.Context, string) (int64, error) {
+	return 0, errNotReplayed
+}
+
+func (c *replayLorenzoClient) ContainsBTCBlock(_ context.Context, blockHash *chainhash.Hash) (*btclctypes.QueryContainsBytesResponse, error) {
+	_, ok := c.known[*blockHash]
+	return &btclctypes.QueryContainsBytesResponse{Contains: ok}, nil
+}
+
+func (c *replayLorenzoClient) BTCHeaderChainTip(context.Context) (*btclctypes.QueryTipResponse, error) {
+	if len(c.tips) > 0 {
+		tip := c.tips[0]
+		c.tips = c.tips[1:]
+		if tip.Error != "" {
+			return nil, errors.New(tip.Error)
+		}
+		hash, err := lorenzotypes.NewBTCHeaderHashBytesFromHex(tip.Hash)
+		if err != nil {
+			return nil, err
+		}
+		return &btclctypes.QueryTipResponse{Header: &btclctypes.BTCHeaderInfo{Hash: &hash, Height: tip.Height}}, nil
+	}
+
+	// the journal is exhausted, so derive the tip from the replayed light client
+	if c.base == nil {
+		return nil, fmt.Errorf("replayed light client has no base header")
+	}
+	tip := &btclctypes.BTCHeaderInfo{Hash: c.base.Hash, Height: c.base.Height}
+	for hash, height := range c.known {
+		if height > tip.Height {
+			hashBytes := lorenzotypes.NewBTCHeaderHashBytesFromChainhash(&hash)
+			tip = &btclctypes.BTCHeaderInfo{Hash: &hashBytes, Height: height}
+		}
+	}
+	return &btclctypes.QueryTipResponse{Header: tip}, nil
+}
+
+func (c *replayLorenzoClient) BTCBaseHeader(context.Context) (*btclctypes.QueryBaseHeaderResponse, error) {
+	if c.base == nil {
+		return nil, fmt.Errorf("replayed light client has no base header")
+	}
+	return &btclctypes.QueryBaseHeaderResponse{Header: c.base}, nil
+}
@@ -0,0 +1,143 @@
+package reporter_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/reporter"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/tracing"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// makeChain returns n linked headers starting at height 0
+func makeChain(n int) []*types.IndexedBlock {
+	ibs := make([]*types.IndexedBlock, 0, n)
+	prev := chainhash.Hash{}
+	for i := 0; i < n; i++ {
+		header := wire.NewBlockHeader(1, &prev, &chainhash.Hash{}, 0x1d00ffff, uint32(i))
+		header.Timestamp = time.Unix(int64(1700000000+i*600), 0)
+		ibs = append(ibs, types.NewIndexedBlock(int32(i), header, nil))
+		prev = header.BlockHash()
+	}
+	return ibs
+}
+
+func writeJournal(t *testing.T, submitted []string, submitErr string) []*journal.Record {
+	chain := makeChain(6)
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := journal.New(&config.JournalConfig{Enabled: true, Path: path, MaxSizeMB: 1}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocks := make([]journal.Header, 0, 5)
+	for _, ib := range chain[:5] {
+		blocks = append(blocks, journal.NewHeader(ib.Height, ib.Header))
+	}
+	j.Record(journal.ModuleReporter, journal.KindBootstrap, &journal.Bootstrap{
+		BaseHeight: 0,
+		BaseHash:   chain[0].BlockHash().String(),
+		Blocks:     blocks,
+	})
+	j.Record(journal.ModuleReporter, journal.KindTip, &journal.Tip{Height: 4, Hash: chain[4].BlockHash().String()})
+	j.RecordBlockEvent(types.NewBlockEvent(types.BlockConnected, chain[5].Height, chain[5].Header))
+	j.Record(journal.ModuleReporter, journal.KindTip, &journal.Tip{Height: 4, Hash: chain[4].BlockHash().String()})
+	j.Record(journal.ModuleReporter, journal.KindSubmission, &journal.Submission{
+		FirstHeight: 5,
+		LastHeight:  5,
+		Submitted:   submitted,
+		Error:       submitErr,
+	})
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := journal.ReadFiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 {
+		t.Fatalf("expected 5 journal records, got %d", len(records))
+	}
+	return records
+}
+
+func TestReplay(t *testing.T) {
+	cfg := &config.ReporterConfig{NetParams: "testnet", BTCCacheSize: 1000, MaxHeadersInMsg: 100}
+	tipHash := makeChain(6)[5].BlockHash().String()
+
+	result, err := reporter.Replay(cfg, zap.NewNop(), writeJournal(t, []string{tipHash}, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Bootstraps != 1 || result.BlockEvents != 1 {
+		t.Fatalf("expected 1 bootstrap and 1 block event, got %d and %d", result.Bootstraps, result.BlockEvents)
+	}
+	if len(result.Divergences) != 0 {
+		t.Fatalf("expected no divergence, got %+v", result.Divergences[0])
+	}
+
+	// a journal claiming the reporter skipped the new block must be reported as diverging
+	result, err = reporter.Replay(cfg, zap.NewNop(), writeJournal(t, []string{}, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Divergences) != 1 {
+		t.Fatalf("expected 1 divergence, got %d", len(result.Divergences))
+	}
+	if d := result.Divergences[0]; len(d.Replayed) != 1 || d.Replayed[0] != tipHash {
+		t.Fatalf("unexpected replayed submissions %v", d.Replayed)
+	}
+
+	// a journaled failure is replayed as one
+	result, err = reporter.Replay(cfg, zap.NewNop(), writeJournal(t, []string{}, "transaction failed with code: 5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Divergences) != 0 {
+		t.Fatalf("expected the failed submission to be replayed without divergence, got %+v", result.Divergences[0])
+	}
+}
+
+func TestReplaySpans(t *testing.T) {
+	exporter := reporter.RecordSpans(t)
+
+	cfg := &config.ReporterConfig{NetParams: "testnet", BTCCacheSize: 1000, MaxHeadersInMsg: 100}
+	tipHash := makeChain(6)[5].BlockHash().String()
+	if _, err := reporter.Replay(cfg, zap.NewNop(), writeJournal(t, []string{tipHash}, "")); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	for _, name := range []string{"reporter.FetchBlock", "reporter.ProcessHeaders", "reporter.ContainsBTCBlock", "reporter.BuildMessages", "reporter.Broadcast"} {
+		if _, ok := spans[name]; !ok {
+			t.Fatalf("expected a %s span, got %v", name, exporter.GetSpans())
+		}
+	}
+
+	// the submission is traced as a child of processing the replayed event
+	process, broadcast := spans["reporter.ProcessHeaders"], spans["reporter.Broadcast"]
+	if broadcast.Parent.SpanID() != process.SpanContext.SpanID() {
+		t.Fatalf("broadcast span is not a child of the processing span")
+	}
+	attrs := attribute.NewSet(process.Attributes...)
+	if height, ok := attrs.Value(tracing.FirstHeightKey); !ok || height.AsInt64() != 5 {
+		t.Fatalf("unexpected first height %v", height)
+	}
+	attrs = attribute.NewSet(broadcast.Attributes...)
+	if hash, ok := attrs.Value(tracing.HashKey); !ok || hash.AsString() != tipHash {
+		t.Fatalf("unexpected broadcast hash %v", hash)
+	}
+}
@@ -1,14 +1,23 @@
 package reporter
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"go.uber.org/zap"
 
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/alert"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/audit"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/btcclient"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/tracing"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
@@ -18,11 +27,14 @@ const (
 	DefaultDelayBlocks                   = 3
 )
 
+var tracer = tracing.Tracer("reporter")
+
 type Reporter struct {
 	Cfg    *config.ReporterConfig
 	logger *zap.SugaredLogger
 
 	btcClient     btcclient.BTCClient
+	btcParams     *chaincfg.Params
 	lorenzoClient LorenzoClient
 
 	// retry attributes
@@ -31,13 +43,30 @@ type Reporter struct {
 
 	// Internal states of the reporter
 	btcCache                      *types.BTCCache
-	reorgList                     *reorgList
+	headerTree                    *types.HeaderTree // known BTC blocks and forks since the cache's finalized part
+	submitQueue                   *submitQueue
+	paused                        bool           // whether submissions are held back for low funds, only used by the block event handler
+	degraded                      *degradedState // set while Lorenzo is unreachable, only used by the block event handler
+	watchdog                      watchdogState
+	btcTip                        atomic.Uint64                // BTC tip at the last query, for the lag metric
+	lorenzoTip                    atomic.Uint64                // tip of Lorenzo's BTC light client, for the lag metric and the watchdog
+	firstSeen                     map[chainhash.Hash]time.Time // when the block events of blocks awaiting inclusion were received
+	firstSeenMu                   sync.Mutex
+	checkpoints                   checkpoints
 	btcConfirmationDepth          uint64
 	checkpointFinalizationTimeout uint64
+	pendingCfg                    atomic.Pointer[config.ReporterConfig] // the latest config update not applied yet
+	reloadChan                    chan struct{}                         // signals the block event handler to apply pendingCfg
 	metrics                       *metrics.ReporterMetrics
+	journal                       *journal.Journal
+	audit                         *audit.Log
+	alerts                        *alert.Dispatcher
 	wg                            sync.WaitGroup
 	started                       bool
+	onFailure                     func(error) // handles panics of the reporter goroutines instead of crashing, if set
 	quit                          chan struct{}
+	ctx                           context.Context // canceled by Stop, so that no RPC call outlives the reporter
+	cancel                        context.CancelFunc
 	quitMu                        sync.Mutex
 
 	delayBlocks uint64
@@ -51,22 +80,45 @@ func New(
 	retrySleepTime,
 	maxRetrySleepTime time.Duration,
 	metrics *metrics.ReporterMetrics,
+	journal *journal.Journal,
+	audit *audit.Log,
+	alerts *alert.Dispatcher,
 ) (*Reporter, error) {
 	logger := parentLogger.With(zap.String("module", "reporter")).Sugar()
 
+	btcParams, err := cfg.Networks.GetBTCParams(cfg.NetParams)
+	if err != nil {
+		return nil, err
+	}
+
+	cps, err := newCheckpoints(cfg.Checkpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	r := &Reporter{
 		Cfg:               cfg,
 		logger:            logger,
 		retrySleepTime:    retrySleepTime,
 		maxRetrySleepTime: maxRetrySleepTime,
 		btcClient:         btcClient,
+		btcParams:         btcParams,
 		lorenzoClient:     lorenzoClient,
-		reorgList:         newReorgList(),
+		submitQueue:       newSubmitQueue(cfg.SubmitBatchWindow, cfg.MaxHeadersInMsg),
+		reloadChan:        make(chan struct{}, 1),
+		firstSeen:         make(map[chainhash.Hash]time.Time),
+		checkpoints:       cps,
 		//TODO: get from config file
 		btcConfirmationDepth:          DefaultBtcConfirmationDepth,
 		checkpointFinalizationTimeout: DefaultCheckpointFinalizationTimeout,
 		metrics:                       metrics,
+		journal:                       journal,
+		audit:                         audit,
+		alerts:                        alerts,
 		quit:                          make(chan struct{}),
+		ctx:                           ctx,
+		cancel:                        cancel,
 
 		delayBlocks: cfg.DelayBlocks,
 	}
@@ -74,8 +126,9 @@ func New(
 	return r, nil
 }
 
-// Start starts the goroutines necessary to manage a lrzrelayer.
-func (r *Reporter) Start() {
+// Start starts the goroutines necessary to manage a lrzrelayer. It returns the failure of the checks and
+// the catch up preceding them, unless it is due to Stop.
+func (r *Reporter) Start() error {
 	r.logger.Infof("Starting reporter. reporter address: %s, delay blocks: %d",
 		r.lorenzoClient.MustGetAddr(), r.delayBlocks)
 
@@ -85,29 +138,98 @@ func (r *Reporter) Start() {
 		// Restart the lrzrelayer goroutines after shutdown finishes.
 		r.WaitForShutdown()
 		r.quit = make(chan struct{})
+		r.ctx, r.cancel = context.WithCancel(context.Background())
 	default:
 		// Ignore when the lrzrelayer is still running.
 		if r.started {
 			r.quitMu.Unlock()
-			return
+			return nil
 		}
 		r.started = true
 	}
+	// count Start itself, so that WaitForShutdown waits for the steps below too, and the
+	// goroutines are never added after a Wait returned
+	r.wg.Add(1)
 	r.quitMu.Unlock()
+	defer r.wg.Done()
+
+	// refuse to run against a BTC node on another network, or one still in initial block download
+	if err := r.preflight(); err != nil {
+		return r.startFailure(err)
+	}
+
+	if err := r.verifyCheckpoints(); err != nil {
+		return r.startFailure(err)
+	}
 
 	if err := r.waitLorenzoCatchUpCloseToBTCTip(); err != nil {
-		panic(err)
+		return r.startFailure(err)
 	}
 
-	r.bootstrapWithRetries(false)
+	if err := r.bootstrapWithRetries(false); err != nil {
+		return r.startFailure(err)
+	}
+	if r.ShuttingDown() {
+		r.logger.Infof("Stopped while starting")
+		return nil
+	}
 
-	r.wg.Add(1)
+	r.wg.Add(2)
 	go r.blockEventHandler()
-
-	// start record time-related metrics
-	r.metrics.RecordMetrics()
+	go r.metricsLoop()
 
 	r.logger.Infof("Successfully started the lrzrelayer reporter")
+	return nil
+}
+
+// SetFailureHandler makes the reporter hand the failures of its goroutines to the given handler instead of crashing
+// the process, so that other components of the process keep running. It has to be called before Start.
+func (r *Reporter) SetFailureHandler(handler func(error)) {
+	r.onFailure = handler
+}
+
+// recoverFailure hands a panic to the failure handler, if one is set. It has to be deferred by the goroutines.
+func (r *Reporter) recoverFailure() {
+	if r.onFailure == nil {
+		return
+	}
+	if rec := recover(); rec != nil {
+		r.fail(types.PanicError(rec))
+	}
+}
+
+// fail hands a failure of the goroutines to the failure handler, or logs it if none is set.
+// Failures while stopping, e.g., of RPC calls canceled by Stop, are only logged.
+func (r *Reporter) fail(err error) {
+	if r.ShuttingDown() {
+		r.logger.Infof("Failed while stopping: %v", err)
+		return
+	}
+	if errors.Is(err, types.ErrChainInconsistency) {
+		r.alerts.Inconsistency(journal.ModuleReporter, err)
+	}
+	if r.onFailure == nil {
+		r.logger.Errorf("Reporter failed: %v", err)
+		return
+	}
+	r.onFailure(err)
+}
+
+// startFailure returns an error of Start, unless it is due to Stop canceling the RPC calls
+func (r *Reporter) startFailure(err error) error {
+	if r.ShuttingDown() {
+		r.logger.Infof("Stopped while starting: %v", err)
+		return nil
+	}
+	return err
+}
+
+// quitCtx atomically reads the context canceled by Stop.
+func (r *Reporter) quitCtx() context.Context {
+	r.quitMu.Lock()
+	ctx := r.ctx
+	r.quitMu.Unlock()
+	return ctx
 }
 
 // quitChan atomically reads the quit channel.
@@ -121,12 +243,14 @@ func (r *Reporter) quitChan() <-chan struct{} {
 // Stop signals all lrzrelayer goroutines to shutdown.
 func (r *Reporter) Stop() {
 	r.quitMu.Lock()
-	quit := r.quit
+	quit, cancel := r.quit, r.cancel
 	r.quitMu.Unlock()
 
 	select {
 	case <-quit:
 	default:
+		// cancel the in-flight RPC calls
+		cancel()
 		// closing the `quit` channel will trigger all select case `<-quit`,
 		// and thus making all handler routines to break the for loop.
 		close(quit)
@@ -0,0 +1,221 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	btclctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/audit"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/btcclient"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// testChain returns n linked headers starting at height 0
+func testChain(n int) []*types.IndexedBlock {
+	ibs := make([]*types.IndexedBlock, 0, n)
+	prev := chainhash.Hash{}
+	for i := 0; i < n; i++ {
+		header := wire.NewBlockHeader(1, &prev, &chainhash.Hash{}, 0x1d00ffff, uint32(i))
+		header.Timestamp = time.Unix(int64(1700000000+i*600), 0)
+		ibs = append(ibs, types.NewIndexedBlock(int32(i), header, nil))
+		prev = header.BlockHash()
+	}
+	return ibs
+}
+
+func newTestReporter(t *testing.T, btcClient btcclient.BTCClient, lorenzoClient LorenzoClient, retrySleepTime time.Duration) *Reporter {
+	cfg := &config.ReporterConfig{NetParams: "testnet", BTCCacheSize: 1000, MaxHeadersInMsg: 100}
+	r, err := New(cfg, zap.NewNop(), btcClient, lorenzoClient, retrySleepTime, 10*retrySleepTime,
+		metrics.NewReporterMetrics(), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// failingLorenzoClient fails every InsertHeaders call, closing attempted on the first one
+type failingLorenzoClient struct {
+	*replayLorenzoClient
+	once      sync.Once
+	attempted chan struct{}
+}
+
+func (c *failingLorenzoClient) InsertHeaders(context.Context, *btclctypes.MsgInsertHeaders) (*pv.RelayerTxResponse, string, error) {
+	c.once.Do(func() { close(c.attempted) })
+	return nil, replaySigner, errors.New("connection refused")
+}
+
+func TestStopCancelsSubmissionRetries(t *testing.T) {
+	chain := testChain(2)
+	lorenzoClient := &failingLorenzoClient{replayLorenzoClient: newReplayLorenzoClient(), attempted: make(chan struct{})}
+	lorenzoClient.insert(chain[0].BlockHash(), 0)
+	// the first retry would only happen after a minute
+	r := newTestReporter(t, newReplayBTCClient(), lorenzoClient, time.Minute)
+
+	errs := make(chan error, 1)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		_, err := r.ProcessHeaders(r.quitCtx(), replaySigner, chain[1:])
+		errs <- err
+	}()
+	<-lorenzoClient.attempted
+
+	stopped := make(chan struct{})
+	go func() {
+		r.Stop()
+		r.WaitForShutdown()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop waited for the retries of the failing submission")
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the submission to be canceled, got %v", err)
+	}
+}
+
+// unreachableLorenzoClient fails every query
+type unreachableLorenzoClient struct {
+	*replayLorenzoClient
+}
+
+func (c *unreachableLorenzoClient) ContainsBTCBlock(context.Context, *chainhash.Hash) (*btclctypes.QueryContainsBytesResponse, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestProcessHeadersErrorClass(t *testing.T) {
+	chain := testChain(2)
+	r := newTestReporter(t, newReplayBTCClient(), &unreachableLorenzoClient{newReplayLorenzoClient()}, time.Millisecond)
+	if _, err := r.ProcessHeaders(context.Background(), replaySigner, chain); !errors.Is(err, types.ErrLorenzoUnavailable) {
+		t.Fatalf("expected a failed query to make Lorenzo unavailable, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := r.ProcessHeaders(ctx, replaySigner, chain)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, types.ErrLorenzoUnavailable) {
+		t.Fatalf("expected a canceled query to keep its error, got %v", err)
+	}
+}
+
+func TestJournalRecordsAcceptedHeaders(t *testing.T) {
+	chain := testChain(4)
+	lorenzoClient := newReplayLorenzoClient()
+	lorenzoClient.insert(chain[0].BlockHash(), 0)
+	// Lorenzo accepts the first msg and rejects the second
+	lorenzoClient.accepted = 1
+	r := newTestReporter(t, newReplayBTCClient(), lorenzoClient, time.Millisecond)
+	r.Cfg.MaxHeadersInMsg = 1
+	journalPath := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := journal.New(&config.JournalConfig{Enabled: true, Path: journalPath}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.journal = j
+
+	if _, err := r.ProcessHeaders(context.Background(), replaySigner, chain[1:]); err == nil {
+		t.Fatal("expected the rejected msg to fail the submission")
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+	records, err := journal.ReadFile(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var submission *journal.Submission
+	for _, record := range records {
+		if record.Kind == journal.KindSubmission {
+			submission = &journal.Submission{}
+			if err := record.Decode(submission); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if submission == nil {
+		t.Fatal("expected the submission to be journaled")
+	}
+	if len(submission.Submitted) != 1 || submission.Submitted[0] != chain[1].BlockHash().String() || submission.Error == "" {
+		t.Fatalf("expected only the accepted header to be journaled as submitted, got %+v", submission)
+	}
+}
+
+// flakyLorenzoClient fails the first failures InsertHeaders calls
+type flakyLorenzoClient struct {
+	*replayLorenzoClient
+	failures int
+}
+
+func (c *flakyLorenzoClient) InsertHeaders(ctx context.Context, msgs *btclctypes.MsgInsertHeaders) (*pv.RelayerTxResponse, string, error) {
+	if c.failures > 0 {
+		c.failures--
+		return nil, replaySigner, errors.New("connection refused")
+	}
+	return c.replayLorenzoClient.InsertHeaders(ctx, msgs)
+}
+
+func TestAuditEveryBroadcast(t *testing.T) {
+	chain := testChain(2)
+	lorenzoClient := newReplayLorenzoClient()
+	lorenzoClient.insert(chain[0].BlockHash(), 0)
+	r := newTestReporter(t, newReplayBTCClient(), &flakyLorenzoClient{replayLorenzoClient: lorenzoClient, failures: 2}, time.Millisecond)
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := audit.New(&config.AuditConfig{Enabled: true, Path: auditPath}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.audit = l
+
+	if _, err := r.ProcessHeaders(context.Background(), replaySigner, chain[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := audit.Verify(auditPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries != 3 {
+		t.Fatalf("expected the 2 failed broadcasts and the successful one to be audited, got %d entries", entries)
+	}
+}
+
+func TestUpdateConfigKeepsLatest(t *testing.T) {
+	r := newTestReporter(t, newReplayBTCClient(), newReplayLorenzoClient(), time.Millisecond)
+	r.submitQueue = newSubmitQueue(0, r.Cfg.MaxHeadersInMsg)
+
+	// no block event handler picks the updates up, so the second one must not block
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.UpdateConfig(&config.ReporterConfig{DelayBlocks: 1, MaxHeadersInMsg: 10})
+		r.UpdateConfig(&config.ReporterConfig{DelayBlocks: 2, MaxHeadersInMsg: 20})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("UpdateConfig blocked without a block event handler")
+	}
+
+	<-r.reloadChan
+	r.applyPendingConfig()
+	if r.delayBlocks != 2 || r.Cfg.MaxHeadersInMsg != 20 {
+		t.Fatalf("applied delay blocks %d and max headers %d, want the latest update", r.delayBlocks, r.Cfg.MaxHeadersInMsg)
+	}
+}
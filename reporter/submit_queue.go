@@ -0,0 +1,245 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/audit"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+const (
+	// FailedSubmitRetryInterval is how long headers whose submission failed wait before they are submitted again,
+	// unless a full batch or a batch without window submits them earlier
+	FailedSubmitRetryInterval = 30 * time.Second
+	// StopFlushTimeout bounds the submission of the pending headers when the reporter stops
+	StopFlushTimeout = 10 * time.Second
+)
+
+// submitQueue holds headers from consecutive block events until they are submitted to Lorenzo together.
+// Pending headers always form a chain, so submitting them in order preserves the order of the events.
+// It is only accessed from the goroutine handling block events.
+type submitQueue struct {
+	window   time.Duration // how long the first pending header may wait for followers; 0 disables batching
+	maxSize  int
+	pending  []*types.IndexedBlock
+	links    []trace.Link // the spans of the events that queued the pending headers
+	deadline time.Time
+}
+
+func newSubmitQueue(window time.Duration, maxHeadersInMsg uint32) *submitQueue {
+	return &submitQueue{
+		window:  window,
+		maxSize: int(maxHeadersInMsg),
+	}
+}
+
+// extends returns whether ib is the child of the last pending header
+func (q *submitQueue) extends(ib *types.IndexedBlock) bool {
+	if len(q.pending) == 0 {
+		return true
+	}
+	tip := q.pending[len(q.pending)-1]
+	return ib.Height == tip.Height+1 && ib.Header.PrevBlock == tip.BlockHash()
+}
+
+// contains returns whether ib is already pending
+func (q *submitQueue) contains(ib *types.IndexedBlock) bool {
+	if len(q.pending) == 0 {
+		return false
+	}
+	idx := int(ib.Height - q.pending[0].Height)
+	return idx >= 0 && idx < len(q.pending) && q.pending[idx].BlockHash() == ib.BlockHash()
+}
+
+// add queues ib, linking the submission to the span of the event that queued it
+func (q *submitQueue) add(ib *types.IndexedBlock, link trace.Link) {
+	if len(q.pending) == 0 {
+		q.deadline = time.Now().Add(q.window)
+	}
+	q.pending = append(q.pending, ib)
+	if link.SpanContext.IsValid() && (len(q.links) == 0 || !q.links[len(q.links)-1].SpanContext.Equal(link.SpanContext)) {
+		q.links = append(q.links, link)
+	}
+}
+
+// due returns whether the pending headers have to be submitted now
+func (q *submitQueue) due() bool {
+	if len(q.pending) == 0 {
+		return false
+	}
+	return q.window == 0 || (q.maxSize > 0 && len(q.pending) >= q.maxSize) || !time.Now().Before(q.deadline)
+}
+
+// timer fires when the batch window of the pending headers closes. It returns nil if nothing is pending.
+func (q *submitQueue) timer() <-chan time.Time {
+	if len(q.pending) == 0 {
+		return nil
+	}
+	return time.After(time.Until(q.deadline))
+}
+
+// retain queues headers whose submission failed again, to be submitted after the given delay. The queue is empty,
+// as submitting took all pending headers.
+func (q *submitQueue) retain(ibs []*types.IndexedBlock, links []trace.Link, delay time.Duration) {
+	q.pending, q.links = ibs, links
+	q.deadline = time.Now().Add(delay)
+}
+
+// take removes and returns all pending headers
+func (q *submitQueue) take() []*types.IndexedBlock {
+	ibs := q.pending
+	q.pending, q.links = nil, nil
+	return ibs
+}
+
+// enqueueHeaders queues headers for submission, submitting the pending ones first if the new headers
+// do not extend them, and submitting the batch as soon as it is full or batching is disabled.
+func (r *Reporter) enqueueHeaders(ctx context.Context, ibs []*types.IndexedBlock) error {
+	// refuse to queue headers that contradict a trusted checkpoint
+	if err := r.checkBlocksAgainstCheckpoints(ibs); err != nil {
+		return err
+	}
+
+	for _, ib := range ibs {
+		if !r.submitQueue.extends(ib) {
+			// catching up with the Lorenzo tip yields headers that are still pending
+			if r.submitQueue.contains(ib) {
+				continue
+			}
+			r.flushSubmitQueue(ctx)
+			if len(r.submitQueue.pending) > 0 {
+				// the held or failed headers were forked off; resuming catches Lorenzo up from the cache anyway
+				r.logger.Debugf("Dropped %d held headers not extended by block %d", len(r.submitQueue.take()), ib.Height)
+			}
+		}
+		r.submitQueue.add(ib, trace.LinkFromContext(ctx))
+		if r.submitQueue.due() {
+			r.flushSubmitQueue(ctx)
+		}
+	}
+	return nil
+}
+
+// submitTimer fires when the pending headers are due, or when it is time to check whether a pause is over
+func (r *Reporter) submitTimer() <-chan time.Time {
+	if r.degraded != nil {
+		// submission resumes once a probe finds Lorenzo reachable
+		return nil
+	}
+	if r.paused && len(r.submitQueue.pending) > 0 {
+		return time.After(PausedCheckInterval)
+	}
+	return r.submitQueue.timer()
+}
+
+// flushSubmitQueue submits all pending headers to Lorenzo. While the signers are paused,
+// the headers are held back, and once they resume every header Lorenzo misses is submitted.
+// Headers whose submission failed are queued again, unless Lorenzo is unavailable.
+func (r *Reporter) flushSubmitQueue(ctx context.Context) {
+	if r.degraded != nil {
+		return
+	}
+	if r.lorenzoClient.Paused() {
+		if !r.paused {
+			r.paused = true
+			r.logger.Warnf("Lorenzo signers are paused for low funds, holding back header submissions")
+		}
+		return
+	}
+
+	links := r.submitQueue.links
+	ibs := r.submitQueue.take()
+	if r.paused {
+		r.paused = false
+		r.logger.Infof("Lorenzo signers resumed, submitting held back headers")
+		// held back headers may have been dropped by forks, so submit everything Lorenzo misses
+		if missed := r.headersMissedByLorenzo(); len(missed) > 0 {
+			ibs = missed
+		}
+		ctx = audit.WithReason(ctx, audit.ReasonBackfill)
+	}
+	if len(ibs) == 0 {
+		return
+	}
+
+	ctx, span := r.startFlushSpan(ctx, links)
+	defer span.End()
+	signer := r.lorenzoClient.MustGetAddr()
+	if _, err := r.ProcessHeaders(ctx, signer, ibs); err != nil {
+		if errors.Is(err, types.ErrLorenzoUnavailable) {
+			r.enterDegraded(err)
+			return
+		}
+		r.logger.Warnf("Failed to submit %d headers, retrying in %v: %v", len(ibs), FailedSubmitRetryInterval, err)
+		r.submitQueue.retain(ibs, links, FailedSubmitRetryInterval)
+	}
+}
+
+// flushOnStop submits the pending headers when the reporter stops, rather than leaving them to the next start
+func (r *Reporter) flushOnStop() {
+	if len(r.submitQueue.pending) == 0 {
+		return
+	}
+	// the quit context is canceled already
+	ctx, cancel := context.WithTimeout(context.Background(), StopFlushTimeout)
+	defer cancel()
+	r.flushSubmitQueue(ctx)
+}
+
+// startFlushSpan starts the span of submitting the headers queued by the events of the given spans. It is a child
+// of the span of ctx only if that event queued all of them, e.g., without a batch window, and a root span otherwise,
+// as the timer or a later event flushes the headers of earlier events.
+func (r *Reporter) startFlushSpan(ctx context.Context, links []trace.Link) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{trace.WithLinks(links...)}
+	if len(links) != 1 || !links[0].SpanContext.Equal(trace.SpanContextFromContext(ctx)) {
+		opts = append(opts, trace.WithNewRoot())
+	}
+	return tracer.Start(ctx, "reporter.FlushHeaders", opts...)
+}
+
+// headersMissedByLorenzo returns the cached headers above the tip of Lorenzo's light client
+func (r *Reporter) headersMissedByLorenzo() []*types.IndexedBlock {
+	lorenzoTip, err := r.queryLorenzoTip()
+	if err != nil {
+		r.logger.Warnf("Failed to query Lorenzo tip: %v", err)
+		return nil
+	}
+	cacheTip := r.btcCache.Tip()
+	if cacheTip == nil || uint64(cacheTip.Height) <= lorenzoTip.Header.Height {
+		return nil
+	}
+	ibs, err := r.btcCache.GetLastBlocks(lorenzoTip.Header.Height + 1)
+	if err != nil {
+		r.logger.Warnf("Failed to get headers missed by Lorenzo from cache: %v", err)
+		return nil
+	}
+	return ibs
+}
+
+// waitUntilUnpaused blocks while the Lorenzo signers are paused for low funds.
+// It returns false if the reporter is asked to stop meanwhile.
+func (r *Reporter) waitUntilUnpaused() bool {
+	if !r.lorenzoClient.Paused() {
+		return true
+	}
+	r.logger.Warnf("Lorenzo signers are paused for low funds, waiting until they are funded")
+
+	quit := r.quitChan()
+	ticker := time.NewTicker(PausedCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !r.lorenzoClient.Paused() {
+				r.logger.Infof("Lorenzo signers resumed")
+				return true
+			}
+		case <-quit:
+			return false
+		}
+	}
+}
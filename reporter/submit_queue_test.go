@@ -0,0 +1,193 @@
+package reporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// newQueueTestReporter returns a reporter whose light client holds the first 3 headers of chain
+func newQueueTestReporter(t *testing.T, chain []*types.IndexedBlock, window time.Duration) (*Reporter, *replayLorenzoClient) {
+	lorenzoClient := newReplayLorenzoClient()
+	for _, ib := range chain[:3] {
+		lorenzoClient.insert(ib.BlockHash(), uint64(ib.Height))
+	}
+	r := newTestReporter(t, newReplayBTCClient(), lorenzoClient, time.Millisecond)
+	r.submitQueue = newSubmitQueue(window, r.Cfg.MaxHeadersInMsg)
+	return r, lorenzoClient
+}
+
+func spansNamed(exporter *tracetest.InMemoryExporter, name string) []tracetest.SpanStub {
+	var spans []tracetest.SpanStub
+	for _, span := range exporter.GetSpans() {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func TestFlushSpansLinkQueuingEvents(t *testing.T) {
+	exporter := recordSpans(t)
+	chain := testChain(6)
+	r, lorenzoClient := newQueueTestReporter(t, chain, time.Hour)
+
+	// two events queue their headers, then the batch window closes
+	var events []trace.SpanContext
+	for _, ib := range chain[3:5] {
+		ctx, span := tracer.Start(context.Background(), "reporter.BlockEvent")
+		if err := r.enqueueHeaders(ctx, []*types.IndexedBlock{ib}); err != nil {
+			t.Fatal(err)
+		}
+		span.End()
+		events = append(events, span.SpanContext())
+	}
+	if len(lorenzoClient.submitted) != 0 {
+		t.Fatal("expected the headers to wait for the batch window")
+	}
+	r.flushSubmitQueue(r.quitCtx())
+	if len(lorenzoClient.submitted) != 2 {
+		t.Fatalf("expected the batch to be submitted, got %v", lorenzoClient.submitted)
+	}
+
+	flushes := spansNamed(exporter, "reporter.FlushHeaders")
+	if len(flushes) != 1 {
+		t.Fatalf("expected one flush span, got %d", len(flushes))
+	}
+	flush := flushes[0]
+	if flush.Parent.IsValid() {
+		t.Fatal("expected the flush of earlier events to be a root span")
+	}
+	if len(flush.Links) != 2 || !flush.Links[0].SpanContext.Equal(events[0]) || !flush.Links[1].SpanContext.Equal(events[1]) {
+		t.Fatalf("expected the flush span to link to both events, got %+v", flush.Links)
+	}
+	for _, name := range []string{"reporter.ProcessHeaders", "reporter.Broadcast"} {
+		for _, span := range spansNamed(exporter, name) {
+			if span.SpanContext.TraceID() != flush.SpanContext.TraceID() {
+				t.Fatalf("expected %s to be traced under the flush span", name)
+			}
+		}
+	}
+
+	// a later event flushing the queue, e.g., while it waits for maturity, does not adopt the submission either
+	queuing, span := tracer.Start(context.Background(), "reporter.BlockEvent")
+	if err := r.enqueueHeaders(queuing, chain[5:]); err != nil {
+		t.Fatal(err)
+	}
+	span.End()
+	flushing, span := tracer.Start(context.Background(), "reporter.WaitMaturity")
+	r.flushSubmitQueue(flushing)
+	span.End()
+	flushes = spansNamed(exporter, "reporter.FlushHeaders")
+	if len(flushes) != 2 || flushes[1].Parent.IsValid() || len(flushes[1].Links) != 1 {
+		t.Fatal("expected the flush of another event's headers to be a root span linking to that event")
+	}
+}
+
+func TestFlushSpanWithoutBatchWindow(t *testing.T) {
+	exporter := recordSpans(t)
+	chain := testChain(4)
+	r, _ := newQueueTestReporter(t, chain, 0)
+
+	ctx, span := tracer.Start(context.Background(), "reporter.BlockEvent")
+	if err := r.enqueueHeaders(ctx, chain[3:]); err != nil {
+		t.Fatal(err)
+	}
+	span.End()
+
+	flushes := spansNamed(exporter, "reporter.FlushHeaders")
+	if len(flushes) != 1 || flushes[0].Parent.SpanID() != span.SpanContext().SpanID() {
+		t.Fatal("expected the flush of the event's own headers to be its child")
+	}
+}
+
+func TestSubmitQueueWindowExpiry(t *testing.T) {
+	chain := testChain(4)
+	r, lorenzoClient := newQueueTestReporter(t, chain, 50*time.Millisecond)
+
+	if err := r.enqueueHeaders(context.Background(), chain[3:]); err != nil {
+		t.Fatal(err)
+	}
+	if len(lorenzoClient.submitted) != 0 || r.submitQueue.due() {
+		t.Fatal("expected the header to wait for the batch window")
+	}
+	select {
+	case <-r.submitTimer():
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the submit timer to fire once the batch window closes")
+	}
+	if !r.submitQueue.due() {
+		t.Fatal("expected the header to be due once the batch window closed")
+	}
+	r.flushSubmitQueue(r.quitCtx())
+	if len(lorenzoClient.submitted) != 1 || r.submitTimer() != nil {
+		t.Fatalf("expected the header to be submitted, got %v", lorenzoClient.submitted)
+	}
+}
+
+func TestSubmitQueueMaxSizeFlush(t *testing.T) {
+	chain := testChain(6)
+	r, lorenzoClient := newQueueTestReporter(t, chain, time.Hour)
+	r.submitQueue.maxSize = 2
+
+	if err := r.enqueueHeaders(context.Background(), chain[3:4]); err != nil {
+		t.Fatal(err)
+	}
+	if len(lorenzoClient.submitted) != 0 {
+		t.Fatal("expected the header to wait for the batch window")
+	}
+	if err := r.enqueueHeaders(context.Background(), chain[4:]); err != nil {
+		t.Fatal(err)
+	}
+	// the full batch is submitted right away, the next header starts a new one
+	if len(lorenzoClient.submitted) != 2 || len(r.submitQueue.pending) != 1 {
+		t.Fatalf("expected a full batch to be submitted, got %v", lorenzoClient.submitted)
+	}
+}
+
+func TestSubmitQueueFlushOnStop(t *testing.T) {
+	chain := testChain(4)
+	r, lorenzoClient := newQueueTestReporter(t, chain, time.Hour)
+	if err := r.enqueueHeaders(context.Background(), chain[3:]); err != nil {
+		t.Fatal(err)
+	}
+
+	r.wg.Add(1)
+	go r.blockEventHandler()
+	r.Stop()
+	r.WaitForShutdown()
+	if len(lorenzoClient.submitted) != 1 {
+		t.Fatalf("expected the pending header to be submitted on stop, got %v", lorenzoClient.submitted)
+	}
+}
+
+func TestSubmitQueueRetainsFailedBatch(t *testing.T) {
+	chain := testChain(5)
+	r, lorenzoClient := newQueueTestReporter(t, chain, 0)
+
+	// Lorenzo rejects the first batch, which waits to be submitted again
+	lorenzoClient.accepted = 0
+	if err := r.enqueueHeaders(context.Background(), chain[3:4]); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.submitQueue.pending) != 1 || r.submitTimer() == nil {
+		t.Fatal("expected the failed batch to be queued again")
+	}
+	if time.Until(r.submitQueue.deadline) <= 0 {
+		t.Fatal("expected the failed batch to wait before it is submitted again")
+	}
+
+	// the next header is submitted together with the failed one
+	lorenzoClient.accepted = -1
+	if err := r.enqueueHeaders(context.Background(), chain[4:]); err != nil {
+		t.Fatal(err)
+	}
+	if len(lorenzoClient.submitted) != 2 || len(r.submitQueue.pending) != 0 {
+		t.Fatalf("expected the failed batch to be submitted with the next header, got %v", lorenzoClient.submitted)
+	}
+}
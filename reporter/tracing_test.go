@@ -0,0 +1,32 @@
+package reporter
+
+import (
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	spanProvider     *sdktrace.TracerProvider
+	spanProviderOnce sync.Once
+)
+
+// recordSpans records the spans started until the end of the test. The tracers of the package stick to the
+// first provider installed, so the tests share one provider and record through their own processors.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	spanProviderOnce.Do(func() { spanProvider = sdktrace.NewTracerProvider() })
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(spanProvider)
+
+	exporter := tracetest.NewInMemoryExporter()
+	processor := sdktrace.NewSimpleSpanProcessor(exporter)
+	spanProvider.RegisterSpanProcessor(processor)
+	t.Cleanup(func() {
+		spanProvider.UnregisterSpanProcessor(processor)
+		otel.SetTracerProvider(previous)
+	})
+	return exporter
+}
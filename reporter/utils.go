@@ -2,16 +2,61 @@ package reporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
 	"time"
 
-	sdkmath "cosmossdk.io/math"
-	"github.com/Lorenzo-Protocol/lorenzo/v3/types/retry"
 	btclctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/tracing"
 	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
 )
 
+// unrecoverableErrors are returned by retryLorenzo right away, like retry.Do of Lorenzo does
+var unrecoverableErrors = []error{
+	btclctypes.ErrHeaderParentDoesNotExist,
+	btclctypes.ErrChainWithNotEnoughWork,
+	btclctypes.ErrInvalidHeader,
+}
+
+// retryLorenzo retries a Lorenzo call with the exponential backoff of retry.Do of Lorenzo, until the backoff exceeds
+// maxRetrySleepTime. It gives up with the error of ctx as soon as ctx is done, so that Stop does not wait for the backoff.
+func (r *Reporter) retryLorenzo(ctx context.Context, call func() error) error {
+	sleep := r.retrySleepTime
+	for {
+		err := call()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		for _, unrecoverable := range unrecoverableErrors {
+			if errors.Is(err, unrecoverable) {
+				return err
+			}
+		}
+
+		// add some randomness to prevent thrashing
+		if sleep > 0 {
+			sleep += time.Duration(rand.Int63n(int64(sleep))) / 2
+		}
+		if sleep > r.maxRetrySleepTime {
+			return err
+		}
+		r.logger.Debugf("Retrying in %v after: %v", sleep, err)
+		select {
+		case <-time.After(sleep):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		sleep *= 2
+	}
+}
+
 func chunkBy[T any](items []T, chunkSize int) (chunks [][]T) {
 	for chunkSize < len(items) {
 		items, chunks = items[chunkSize:], append(chunks, items[0:chunkSize:chunkSize])
@@ -21,7 +66,7 @@ func chunkBy[T any](items []T, chunkSize int) (chunks [][]T) {
 
 // getHeaderMsgsToSubmit creates a set of MsgInsertHeaders messages corresponding to headers that
 // should be submitted to Lorenzo from a given set of indexed blocks
-func (r *Reporter) getHeaderMsgsToSubmit(signer string, ibs []*types.IndexedBlock) ([]*btclctypes.MsgInsertHeaders, error) {
+func (r *Reporter) getHeaderMsgsToSubmit(ctx context.Context, signer string, ibs []*types.IndexedBlock) ([]*btclctypes.MsgInsertHeaders, error) {
 	var (
 		startPoint  = -1
 		ibsToSubmit []*types.IndexedBlock
@@ -31,13 +76,21 @@ func (r *Reporter) getHeaderMsgsToSubmit(signer string, ibs []*types.IndexedBloc
 	// find the first header that is not contained in Lorenzo header chain, then submit since this header
 	for i, header := range ibs {
 		blockHash := header.BlockHash()
+		queryCtx, span := tracer.Start(ctx, "reporter.ContainsBTCBlock", trace.WithAttributes(
+			tracing.HeightKey.Int64(int64(header.Height)),
+			tracing.HashKey.String(blockHash.String()),
+		))
 		var res *btclctypes.QueryContainsBytesResponse
-		err = retry.Do(r.retrySleepTime, r.maxRetrySleepTime, func() error {
-			res, err = r.lorenzoClient.ContainsBTCBlock(&blockHash)
+		err = r.retryLorenzo(queryCtx, func() error {
+			res, err = r.lorenzoClient.ContainsBTCBlock(queryCtx, &blockHash)
 			return err
 		})
+		tracing.End(span, err)
 		if err != nil {
-			return nil, err
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to check whether Lorenzo contains BTC block %v: %w: %w", blockHash, types.ErrLorenzoUnavailable, err)
 		}
 		if !res.Contains {
 			startPoint = i
@@ -53,6 +106,11 @@ func (r *Reporter) getHeaderMsgsToSubmit(signer string, ibs []*types.IndexedBloc
 
 	// wrap the headers to MsgInsertHeaders msgs from the subset of indexed blocks
 	ibsToSubmit = ibs[startPoint:]
+	_, span := tracer.Start(ctx, "reporter.BuildMessages", trace.WithAttributes(
+		tracing.FirstHeightKey.Int64(int64(ibsToSubmit[0].Height)),
+		tracing.LastHeightKey.Int64(int64(ibsToSubmit[len(ibsToSubmit)-1].Height)),
+	))
+	defer span.End()
 
 	blockChunks := chunkBy(ibsToSubmit, int(r.Cfg.MaxHeadersInMsg))
 
@@ -66,70 +124,91 @@ func (r *Reporter) getHeaderMsgsToSubmit(signer string, ibs []*types.IndexedBloc
 	return headerMsgsToSubmit, nil
 }
 
-func (r *Reporter) submitHeaderMsgs(msg *btclctypes.MsgInsertHeaders) error {
-	// submit the headers
-	err := retry.Do(r.retrySleepTime, r.maxRetrySleepTime, func() error {
-		res, err := r.lorenzoClient.InsertHeaders(context.Background(), msg)
+// submitHeaderMsgs submits msg, whose first header is at firstHeight
+func (r *Reporter) submitHeaderMsgs(ctx context.Context, msg *btclctypes.MsgInsertHeaders, firstHeight uint64) error {
+	ctx, span := tracer.Start(ctx, "reporter.Broadcast", trace.WithAttributes(
+		tracing.HashKey.String(msg.Headers[0].Hash().MarshalHex()),
+		tracing.HeadersKey.Int(len(msg.Headers)),
+	))
+	// submit the headers, auditing every broadcast
+	err := r.retryLorenzo(ctx, func() error {
+		start := time.Now()
+		res, signer, err := r.lorenzoClient.InsertHeaders(ctx, msg)
+		r.auditInsertHeaders(ctx, msg, signer, firstHeight, res, err)
 		if err != nil {
+			r.metrics.SubmitDurationHistogramVec.WithLabelValues("failure").Observe(time.Since(start).Seconds())
 			return err
 		}
+		r.metrics.SubmitDurationHistogramVec.WithLabelValues("success").Observe(time.Since(start).Seconds())
+		span.SetAttributes(tracing.TxHashKey.String(res.TxHash), tracing.TxCodeKey.Int64(int64(res.Code)))
 		r.logger.Infof("Successfully submitted %d headers to Lorenzo with response code %v", len(msg.Headers), res.Code)
 		return nil
 	})
+	tracing.End(span, err)
 	if err != nil {
 		r.metrics.FailedHeadersCounter.Add(float64(len(msg.Headers)))
 		return fmt.Errorf("failed to submit headers: %w", err)
 	}
 
-	// update metrics
-	r.metrics.SuccessfulHeadersCounter.Add(float64(len(msg.Headers)))
-	r.metrics.SecondsSinceLastHeaderGauge.Set(0)
-	for _, header := range msg.Headers {
-		r.metrics.NewReportedHeaderGaugeVec.WithLabelValues(header.Hash().String()).SetToCurrentTime()
-	}
+	r.alerts.Relayed(journal.ModuleReporter)
+	r.recordInclusion(msg, firstHeight+uint64(len(msg.Headers))-1)
 
 	return err
 }
 
 // ProcessHeaders extracts and reports headers from a list of blocks
 // It returns the number of headers that need to be reported (after deduplication)
-func (r *Reporter) ProcessHeaders(signer string, ibs []*types.IndexedBlock) (int, error) {
+func (r *Reporter) ProcessHeaders(ctx context.Context, signer string, ibs []*types.IndexedBlock) (numSubmitted int, err error) {
+	var submittedMsgs []*btclctypes.MsgInsertHeaders
+	ctx, span := tracer.Start(ctx, "reporter.ProcessHeaders", trace.WithAttributes(
+		tracing.FirstHeightKey.Int64(int64(ibs[0].Height)),
+		tracing.LastHeightKey.Int64(int64(ibs[len(ibs)-1].Height)),
+	))
 	defer func(start time.Time) {
 		r.logger.Infof("Processed block height %d to %d, time used: %v", ibs[0].Height, ibs[len(ibs)-1].Height, time.Since(start))
+		r.recordSubmission(ibs, submittedMsgs, err)
+		span.SetAttributes(tracing.HeadersKey.Int(numSubmitted))
+		tracing.End(span, err)
 	}(time.Now())
 
+	// refuse to relay headers that contradict a trusted checkpoint
+	if err := r.checkBlocksAgainstCheckpoints(ibs); err != nil {
+		return 0, err
+	}
+	if r.lorenzoClient.Paused() {
+		return 0, types.ErrSignersPaused
+	}
+
 	// get a list of MsgInsertHeader msgs with headers to be submitted
-	headerMsgsToSubmit, err := r.getHeaderMsgsToSubmit(signer, ibs)
+	headerMsgsToSubmit, err := r.getHeaderMsgsToSubmit(ctx, signer, ibs)
 	if err != nil {
 		return 0, fmt.Errorf("failed to find headers to submit: %w", err)
 	}
 	// skip if no header to submit
 	if len(headerMsgsToSubmit) == 0 {
 		r.logger.Info("No new headers to submit")
+		// Lorenzo has the headers already, so relaying keeps up
+		r.alerts.Relayed(journal.ModuleReporter)
 		return 0, nil
 	}
 
-	var numSubmitted int
-	// submit each chunk of headers
+	// submit each chunk of headers, the chunks end with the last block
+	firstHeight := uint64(ibs[len(ibs)-1].Height) + 1
 	for _, msgs := range headerMsgsToSubmit {
-		if err := r.submitHeaderMsgs(msgs); err != nil {
+		firstHeight -= uint64(len(msgs.Headers))
+	}
+	for _, msgs := range headerMsgsToSubmit {
+		if err := r.submitHeaderMsgs(ctx, msgs, firstHeight); err != nil {
 			return 0, fmt.Errorf("failed to submit headers: %w", err)
 		}
+		submittedMsgs = append(submittedMsgs, msgs)
+		firstHeight += uint64(len(msgs.Headers))
 		numSubmitted += len(msgs.Headers)
 	}
 
 	return numSubmitted, err
 }
 
-func calculateBranchWork(branch []*types.IndexedBlock) sdkmath.Uint {
-	var currenWork = sdkmath.ZeroUint()
-	for _, h := range branch {
-		headerWork := btclctypes.CalcHeaderWork(h.Header)
-		currenWork = btclctypes.CumulativeWork(headerWork, currenWork)
-	}
-	return currenWork
-}
-
 // push msg to channel c, or quit if quit channel is closed
 func PushOrQuit[T any](c chan<- T, msg T, quit <-chan struct{}) {
 	select {
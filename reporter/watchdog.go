@@ -0,0 +1,76 @@
+package reporter
+
+import (
+	"context"
+	"time"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+)
+
+// WatchdogCheckInterval is how often the watchdog compares the BTC tip with the Lorenzo tip
+const WatchdogCheckInterval = time.Minute
+
+// watchdogState tracks relay progress for the watchdog. It is only used by the block event handler.
+type watchdogState struct {
+	relayed     uint64    // Lorenzo tip at the last check
+	behindSince time.Time // when the reporter was first found behind the BTC tip without progress, zero if it is not
+}
+
+// checkStall re-subscribes to BTC blocks and bootstraps again if the Lorenzo tip did not move for
+// Cfg.StallAfter while the BTC tip had mature blocks to relay, e.g., because block events silently
+// stopped arriving. Blocks in the BTC cache that never reached Lorenzo are no progress. Lorenzo outages and paused signers are handled elsewhere and do not count as stalls.
+// It returns the failure of the bootstrap, if the reporter intervened and the bootstrap gave up.
+func (r *Reporter) checkStall(ctx context.Context) error {
+	w := &r.watchdog
+	if r.Cfg.StallAfter == 0 || r.degraded != nil || r.paused || r.btcCache == nil || r.btcCache.Tip() == nil {
+		w.behindSince = time.Time{}
+		return nil
+	}
+
+	_, btcTip, err := r.btcClient.GetBestBlock(ctx)
+	if err != nil {
+		// an unavailable BTC node is not a stall of the reporter
+		r.logger.Debugf("Watchdog failed to get the BTC tip: %v", err)
+		return nil
+	}
+	r.recordBTCTip(btcTip)
+	// the last tip seen in a query or a submission, also moved by other relayers
+	relayed := r.lorenzoTip.Load()
+	behind := btcTip >= r.delayBlocks+relayed+1
+
+	now := time.Now()
+	var bootstrapErr error
+	switch {
+	case !behind:
+		w.behindSince = time.Time{}
+	case relayed != w.relayed || w.behindSince.IsZero():
+		w.behindSince = now
+	case now.Sub(w.behindSince) >= r.Cfg.StallAfter:
+		bootstrapErr = r.intervene(btcTip, relayed, now.Sub(w.behindSince))
+		// give the intervention a full period to take effect
+		w.behindSince = time.Now()
+	}
+	w.relayed = relayed
+	return bootstrapErr
+}
+
+// intervene re-subscribes to BTC blocks and bootstraps again, recording the intervention
+func (r *Reporter) intervene(btcTip uint64, relayed uint64, stalledFor time.Duration) error {
+	r.logger.Warnf("Relayed nothing for %v while the BTC tip %d is ahead of the Lorenzo tip %d, "+
+		"re-subscribing to BTC blocks and bootstrapping again", stalledFor.Round(time.Second), btcTip, relayed)
+	r.metrics.WatchdogInterventionsCounter.Inc()
+
+	intervention := &journal.Intervention{
+		Action:     "resubscribe-bootstrap",
+		SourceTip:  btcTip,
+		RelayedTip: relayed,
+		StalledFor: stalledFor.Round(time.Second).String(),
+	}
+	if err := r.btcClient.ResubscribeBlocks(); err != nil {
+		r.logger.Errorf("Failed to re-subscribe to BTC blocks: %v", err)
+		intervention.Error = err.Error()
+	}
+	r.journal.Record(journal.ModuleReporter, journal.KindIntervention, intervention)
+
+	return r.bootstrapWithRetries(true)
+}
@@ -0,0 +1,140 @@
+package reporter
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	lorenzotypes "github.com/Lorenzo-Protocol/lorenzo/v3/types"
+	btclctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/journal"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// syncedBTCClient is a replayed BTC node that reports itself synced
+type syncedBTCClient struct {
+	*replayBTCClient
+}
+
+func (c *syncedBTCClient) GetBlockChainInfo(context.Context) (*btcjson.GetBlockChainInfoResult, error) {
+	return &btcjson.GetBlockChainInfoResult{}, nil
+}
+
+func TestWatchdogInterventions(t *testing.T) {
+	chain := testChain(5)
+	btcClient := &syncedBTCClient{newReplayBTCClient()}
+	btcClient.reset(chain[:3])
+	lorenzoClient := newReplayLorenzoClient()
+	lorenzoClient.base = lorenzoHeaderInfo(chain[0])
+	for _, ib := range chain[:3] {
+		lorenzoClient.insert(ib.BlockHash(), uint64(ib.Height))
+	}
+
+	r := newTestReporter(t, btcClient, lorenzoClient, time.Millisecond)
+	r.Cfg.StallAfter = time.Hour
+	journalPath := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := journal.New(&config.JournalConfig{Enabled: true, Path: journalPath}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.journal = j
+	if r.btcCache, err = types.NewBTCCache(r.Cfg.BTCCacheSize); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.btcCache.Init(chain[:3]); err != nil {
+		t.Fatal(err)
+	}
+	// the tip bootstrap found on Lorenzo
+	r.recordLorenzoTip(2)
+	interventions := func() float64 { return testutil.ToFloat64(r.metrics.WatchdogInterventionsCounter) }
+	stalledSince := func() { r.watchdog.behindSince = time.Now().Add(-2 * r.Cfg.StallAfter) }
+
+	// relaying keeps up with the BTC tip
+	r.checkStall(context.Background())
+	if !r.watchdog.behindSince.IsZero() {
+		t.Fatal("expected the watchdog not to count a reporter at the BTC tip as behind")
+	}
+
+	// the BTC tip moves on while the reporter relays nothing
+	btcClient.connect(chain[3])
+	btcClient.connect(chain[4])
+	r.checkStall(context.Background())
+	if r.watchdog.behindSince.IsZero() || interventions() != 0 {
+		t.Fatal("expected the watchdog to start counting the stall without intervening")
+	}
+
+	// blocks reaching the BTC cache but not Lorenzo are no progress
+	r.watchdog.behindSince = time.Now().Add(-r.Cfg.StallAfter / 2)
+	behindSince := r.watchdog.behindSince
+	r.btcCache.Add(chain[3])
+	r.checkStall(context.Background())
+	if !r.watchdog.behindSince.Equal(behindSince) || interventions() != 0 {
+		t.Fatal("expected the stall to go on while the cached blocks are not on Lorenzo")
+	}
+
+	// progress resets the stall
+	stalledSince()
+	r.watchdog.relayed = 1
+	r.checkStall(context.Background())
+	if time.Since(r.watchdog.behindSince) >= r.Cfg.StallAfter || interventions() != 0 {
+		t.Fatal("expected relay progress to reset the stall")
+	}
+
+	// Lorenzo outages and paused signers are no stalls
+	stalledSince()
+	r.degraded = &degradedState{}
+	r.checkStall(context.Background())
+	r.degraded = nil
+	r.paused = true
+	r.checkStall(context.Background())
+	r.paused = false
+	if !r.watchdog.behindSince.IsZero() || interventions() != 0 {
+		t.Fatal("expected the watchdog to skip the check while degraded or paused")
+	}
+
+	// a stall beyond the threshold re-subscribes and bootstraps, which relays the missing headers
+	r.checkStall(context.Background())
+	stalledSince()
+	r.checkStall(context.Background())
+	if interventions() != 1 {
+		t.Fatalf("expected one intervention, got %v", interventions())
+	}
+	if len(lorenzoClient.submitted) != 2 {
+		t.Fatalf("expected the bootstrap to relay the 2 missing headers, got %v", lorenzoClient.submitted)
+	}
+
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+	records, err := journal.ReadFile(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intervention *journal.Intervention
+	for _, record := range records {
+		if record.Kind == journal.KindIntervention {
+			intervention = &journal.Intervention{}
+			if err := record.Decode(intervention); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if intervention == nil {
+		t.Fatal("expected the intervention to be journaled")
+	}
+	if intervention.Action != "resubscribe-bootstrap" || intervention.SourceTip != 4 || intervention.RelayedTip != 2 || intervention.Error != "" {
+		t.Fatalf("unexpected intervention record: %+v", intervention)
+	}
+}
+
+func lorenzoHeaderInfo(ib *types.IndexedBlock) *btclctypes.BTCHeaderInfo {
+	hash := ib.BlockHash()
+	hashBytes := lorenzotypes.NewBTCHeaderHashBytesFromChainhash(&hash)
+	return &btclctypes.BTCHeaderInfo{Hash: &hashBytes, Height: uint64(ib.Height)}
+}
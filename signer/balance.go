@@ -0,0 +1,168 @@
+package signer
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
+// Start checks the balance of every key, and keeps checking it periodically while balance monitoring is enabled.
+// Keys below the critical balance stop broadcasting until they are funded again. Only the first call starts the monitoring.
+func (g *Group) Start() {
+	g.mu.Lock()
+	if g.started {
+		g.mu.Unlock()
+		return
+	}
+	g.started = true
+	g.mu.Unlock()
+
+	// know whether we are paused before the reporters start broadcasting
+	g.checkBalances()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		for {
+			var tick <-chan time.Time
+			if interval, _, _ := g.balanceSettings(); interval > 0 {
+				tick = time.After(interval)
+			}
+			select {
+			case <-tick:
+				g.checkBalances()
+			case <-g.updated:
+				g.checkBalances()
+			case <-g.quit:
+				return
+			}
+		}
+	}()
+}
+
+// UpdateConfig applies the balance check interval and thresholds of the given config, which has to be valid.
+// The balances are checked again right away against the new thresholds.
+func (g *Group) UpdateConfig(cfg *config.SignersConfig) {
+	warning, critical, err := cfg.Thresholds()
+	if err != nil {
+		g.logger.Sugar().Errorf("Ignored invalid config update: %v", err)
+		return
+	}
+
+	g.balanceMu.Lock()
+	g.checkInterval = cfg.BalanceCheckInterval
+	g.warning = warning
+	g.critical = critical
+	g.balanceMu.Unlock()
+
+	select {
+	case g.updated <- struct{}{}:
+	default:
+	}
+	g.logger.Sugar().Infof("Applied config update. balance check interval: %v, warning balance: %v, critical balance: %v",
+		cfg.BalanceCheckInterval, warning, critical)
+}
+
+func (g *Group) balanceSettings() (time.Duration, *sdk.Coin, *sdk.Coin) {
+	g.balanceMu.RLock()
+	defer g.balanceMu.RUnlock()
+
+	if g.warning == nil && g.critical == nil {
+		return 0, nil, nil
+	}
+	return g.checkInterval, g.warning, g.critical
+}
+
+func (g *Group) checkBalances() {
+	g.mu.Lock()
+	signers := append([]*signer(nil), g.signers...)
+	pools := append([]*Pool(nil), g.pools...)
+	g.mu.Unlock()
+
+	interval, warning, critical := g.balanceSettings()
+	for _, s := range signers {
+		if interval == 0 {
+			// monitoring is disabled, so no key can be known to be low on funds
+			s.lowFunds.Store(false)
+			continue
+		}
+		g.checkBalance(s, warning, critical)
+	}
+
+	for _, p := range pools {
+		p.updatePaused(critical)
+	}
+}
+
+// updatePaused records whether all keys of the pool are below the critical balance after a balance check
+func (p *Pool) updatePaused(critical *sdk.Coin) {
+	paused := p.Paused()
+	if paused {
+		p.group.metrics.PausedGaugeVec.WithLabelValues(p.reporter).Set(1)
+	} else {
+		p.group.metrics.PausedGaugeVec.WithLabelValues(p.reporter).Set(0)
+	}
+
+	wasPaused := p.paused.Swap(paused)
+	if paused && !wasPaused {
+		p.logger.Errorf("All keys are below the critical balance %s, pausing broadcasting until they are funded", critical)
+	} else if !paused && wasPaused {
+		p.logger.Infof("Keys are funded again, resuming broadcasting")
+	}
+}
+
+func (g *Group) checkBalance(s *signer, warning, critical *sdk.Coin) {
+	logger := g.logger.Sugar()
+	denom := thresholdsDenom(warning, critical)
+	balance, err := queryBalance(g.ctx, s, denom)
+	if err != nil {
+		// keep the last known state, a failing query says nothing about the funds
+		logger.Warnf("Failed to query balance of key %s: %v", s.key, err)
+		return
+	}
+	amount, _ := new(big.Float).SetInt(balance.Amount.BigInt()).Float64()
+	g.metrics.BalanceGaugeVec.WithLabelValues(s.key, denom).Set(amount)
+
+	switch {
+	case critical != nil && balance.IsLT(*critical):
+		if !s.lowFunds.Swap(true) {
+			logger.Errorf("Balance %s of key %s (%s) is below the critical balance %s, the key stops broadcasting",
+				balance, s.key, s.address, critical)
+		}
+		g.alerts.LowBalance(s.key, s.address, balance.String(), critical.String(), true)
+		return
+	case warning != nil && balance.IsLT(*warning):
+		logger.Warnf("Balance %s of key %s (%s) is below the warning balance %s", balance, s.key, s.address, warning)
+		g.alerts.LowBalance(s.key, s.address, balance.String(), warning.String(), false)
+	}
+	if s.lowFunds.Swap(false) {
+		logger.Infof("Key %s (%s) is funded again with %s, resuming broadcasting", s.key, s.address, balance)
+	}
+}
+
+// thresholdsDenom is the denom of the balance thresholds
+func thresholdsDenom(warning, critical *sdk.Coin) string {
+	if critical != nil {
+		return critical.Denom
+	}
+	return warning.Denom
+}
+
+func queryBalance(ctx context.Context, s *signer, denom string) (sdk.Coin, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.client.GetConfig().Timeout)
+	defer cancel()
+
+	queryClient := banktypes.NewQueryClient(client.Context{Client: s.client.RPCClient})
+	res, err := queryClient.Balance(ctx, &banktypes.QueryBalanceRequest{Address: s.address, Denom: denom})
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+	return *res.Balance, nil
+}
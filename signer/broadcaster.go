@@ -0,0 +1,177 @@
+package signer
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	lrzcfg "github.com/Lorenzo-Protocol/lorenzo-sdk/v3/config"
+	lorenzo "github.com/Lorenzo-Protocol/lorenzo/v3/app"
+	"github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/juju/fslock"
+	"go.uber.org/zap"
+)
+
+// inclusionPollInterval is how often a broadcast tx is looked up until it is included in a block
+const inclusionPollInterval = 100 * time.Millisecond
+
+// broadcaster signs and broadcasts the txs of a single key
+type broadcaster interface {
+	// Broadcast signs msg with the given account sequence and waits until the tx is included in a block
+	Broadcast(ctx context.Context, msg sdk.Msg, sequence uint64) (*pv.RelayerTxResponse, error)
+	// AccountSequence queries the sequence of the account of the key on Lorenzo
+	AccountSequence(ctx context.Context) (uint64, error)
+	Stop() error
+}
+
+// keyBroadcaster broadcasts with a key of the keyring of the Lorenzo config. The SDK client picks the account
+// sequence on its own, so the txs are built here with the cosmos provider underneath it.
+type keyBroadcaster struct {
+	provider *cosmos.CosmosProvider
+	cfg      *lrzcfg.LorenzoConfig
+	address  string
+}
+
+func newKeyBroadcaster(cfg *lrzcfg.LorenzoConfig, address string, logger *zap.Logger) (*keyBroadcaster, error) {
+	p, err := cfg.ToCosmosProviderConfig().NewProvider(logger, "", false, "lorenzo")
+	if err != nil {
+		return nil, err
+	}
+	cp := p.(*cosmos.CosmosProvider)
+	cp.PCfg.KeyDirectory = cfg.KeyDirectory
+	encCfg := lorenzo.MakeEncodingConfig()
+	cp.Cdc = cosmos.Codec{
+		InterfaceRegistry: encCfg.InterfaceRegistry,
+		Marshaler:         encCfg.Codec,
+		TxConfig:          encCfg.TxConfig,
+		Amino:             encCfg.Amino,
+	}
+	if err := cp.Init(context.Background()); err != nil {
+		return nil, err
+	}
+	return &keyBroadcaster{provider: cp, cfg: cfg, address: address}, nil
+}
+
+func (b *keyBroadcaster) Broadcast(ctx context.Context, msg sdk.Msg, sequence uint64) (*pv.RelayerTxResponse, error) {
+	var (
+		txBytes []byte
+		err     error
+	)
+	if lockErr := b.withKeyringLock(func() {
+		txBytes, err = b.sign(ctx, msg, sequence)
+	}); lockErr != nil {
+		return nil, lockErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := b.provider.RPCClient.BroadcastTxSync(ctx, txBytes)
+	if err != nil {
+		return nil, err
+	}
+	if res.Code != 0 {
+		rlyRes := &pv.RelayerTxResponse{TxHash: res.Hash.String(), Codespace: res.Codespace, Code: res.Code, Data: res.Data.String()}
+		return rlyRes, fmt.Errorf("transaction failed with code %d: %s", res.Code, res.Log)
+	}
+	return b.waitForInclusion(ctx, res.Hash)
+}
+
+// sign builds msg into a tx signed with the given account sequence
+func (b *keyBroadcaster) sign(ctx context.Context, msg sdk.Msg, sequence uint64) ([]byte, error) {
+	done := b.provider.SetSDKContext()
+	defer done()
+
+	txf, err := b.provider.PrepareFactory(b.provider.TxFactory().WithSequence(sequence), b.cfg.Key)
+	if err != nil {
+		return nil, err
+	}
+	_, gas, err := b.provider.CalculateGas(ctx, txf, b.cfg.Key, msg)
+	if err != nil {
+		return nil, err
+	}
+	txb, err := txf.WithGas(gas).BuildUnsignedTx(msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Sign(txf, b.cfg.Key, txb, false); err != nil {
+		return nil, err
+	}
+	return b.provider.Cdc.TxConfig.TxEncoder()(txb.GetTx())
+}
+
+// withKeyringLock runs accessFunc holding the file lock the SDK client takes on the keyring
+func (b *keyBroadcaster) withKeyringLock(accessFunc func()) error {
+	lockFilePath := path.Join(b.cfg.KeyDirectory, "keys.lock")
+	lock := fslock.New(lockFilePath)
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("failed to acquire file system lock (%s): %w", lockFilePath, err)
+	}
+	defer lock.Unlock()
+
+	accessFunc()
+	return nil
+}
+
+// waitForInclusion waits until the tx with the given hash is included in a block, for at most the block timeout
+func (b *keyBroadcaster) waitForInclusion(ctx context.Context, hash bytes.HexBytes) (*pv.RelayerTxResponse, error) {
+	if b.cfg.BlockTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.cfg.BlockTimeout)
+		defer cancel()
+	}
+	for {
+		select {
+		case <-time.After(inclusionPollInterval):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("tx %s was not included in a block: %w", hash, ctx.Err())
+		}
+
+		res, err := b.provider.RPCClient.Tx(ctx, hash, false)
+		if err != nil {
+			if strings.Contains(err.Error(), "transaction indexing is disabled") {
+				return nil, fmt.Errorf("cannot determine whether tx %s was included, tx indexing is disabled on the Lorenzo node", hash)
+			}
+			continue
+		}
+		rlyRes := &pv.RelayerTxResponse{
+			Height:    res.Height,
+			TxHash:    res.Hash.String(),
+			Codespace: res.TxResult.Codespace,
+			Code:      res.TxResult.Code,
+			Data:      strings.ToUpper(hex.EncodeToString(res.TxResult.Data)),
+		}
+		if res.TxResult.Code != 0 {
+			return rlyRes, fmt.Errorf("transaction failed with code %d: %s", res.TxResult.Code, res.TxResult.Log)
+		}
+		return rlyRes, nil
+	}
+}
+
+func (b *keyBroadcaster) AccountSequence(ctx context.Context) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.cfg.Timeout)
+	defer cancel()
+
+	queryClient := authtypes.NewQueryClient(client.Context{Client: b.provider.RPCClient})
+	res, err := queryClient.AccountInfo(ctx, &authtypes.QueryAccountInfoRequest{Address: b.address})
+	if err != nil {
+		return 0, err
+	}
+	return res.Info.Sequence, nil
+}
+
+func (b *keyBroadcaster) Stop() error {
+	if !b.provider.RPCClient.IsRunning() {
+		return nil
+	}
+	return b.provider.RPCClient.Stop()
+}
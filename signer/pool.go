@@ -0,0 +1,332 @@
+// Package signer spreads the Lorenzo txs of a reporter over one or more keys, so that reporters
+// do not contend for the account sequence of a shared key.
+package signer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lrzclient "github.com/Lorenzo-Protocol/lorenzo-sdk/v3/client"
+	lrzcfg "github.com/Lorenzo-Protocol/lorenzo-sdk/v3/config"
+	bnblctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/bnblightclient/types"
+	btclctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/alert"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// signer is a Lorenzo client signing with a single key
+type signer struct {
+	key     string
+	address string
+	client  *lrzclient.Client // for the queries of the key
+	txs     broadcaster
+
+	mu       sync.Mutex // serializes the txs of the key within the process
+	sequence uint64     // the account sequence the next tx of the key is signed with
+
+	lowFunds atomic.Bool // whether the balance is below the critical balance
+}
+
+// Pool signs the Lorenzo txs of one reporter with the keys assigned to it, in turn.
+// Queries go through the client of the first key.
+type Pool struct {
+	*lrzclient.Client
+
+	group    *Group
+	reporter string
+	signers  []*signer
+	next     atomic.Uint64
+	paused   atomic.Bool // whether all keys were below the critical balance at the last balance check
+	logger   *zap.SugaredLogger
+}
+
+// Group holds the Lorenzo signers of the reporters running in one process, one client per key.
+// Reporters assigned the same key share its client and account sequence.
+type Group struct {
+	cfg        *config.SignersConfig
+	lorenzoCfg *lrzcfg.LorenzoConfig
+	retries    uint
+	metrics    *metrics.SignerMetrics
+	alerts     *alert.Dispatcher
+	logger     *zap.Logger
+
+	mu      sync.Mutex
+	signers []*signer // every key of every pool, in creation order
+	pools   []*Pool
+
+	ctx    context.Context // canceled by Stop, so that no query of the group outlives it
+	cancel context.CancelFunc
+
+	// balance monitoring, the settings can be updated at runtime
+	balanceMu     sync.RWMutex
+	checkInterval time.Duration
+	warning       *sdk.Coin
+	critical      *sdk.Coin
+	updated       chan struct{} // wakes the balance monitoring up after an update
+	started       bool
+	stopOnce      sync.Once
+	stopErr       error
+	wg            sync.WaitGroup
+	quit          chan struct{}
+}
+
+// New creates the signers of the given reporter (config.SignerReporter|config.SignerBNBReporter),
+// one Lorenzo client per key
+func New(
+	cfg *config.SignersConfig,
+	lorenzoCfg *lrzcfg.LorenzoConfig,
+	reporter string,
+	parentLogger *zap.Logger,
+	metrics *metrics.SignerMetrics,
+	alerts *alert.Dispatcher,
+) (*Pool, error) {
+	g, err := NewGroup(cfg, lorenzoCfg, parentLogger, metrics, alerts)
+	if err != nil {
+		return nil, err
+	}
+	return g.Pool(reporter)
+}
+
+// NewGroup creates a group without keys, the keys are opened by the pools of the reporters
+func NewGroup(
+	cfg *config.SignersConfig,
+	lorenzoCfg *lrzcfg.LorenzoConfig,
+	parentLogger *zap.Logger,
+	metrics *metrics.SignerMetrics,
+	alerts *alert.Dispatcher,
+) (*Group, error) {
+	warning, critical, err := cfg.Thresholds()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Group{
+		cfg:           cfg,
+		lorenzoCfg:    lorenzoCfg,
+		retries:       cfg.SequenceRetries,
+		metrics:       metrics,
+		alerts:        alerts,
+		logger:        parentLogger.With(zap.String("module", "signer")),
+		checkInterval: cfg.BalanceCheckInterval,
+		warning:       warning,
+		critical:      critical,
+		updated:       make(chan struct{}, 1),
+		quit:          make(chan struct{}),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+	if g.retries == 0 {
+		g.retries = config.DefaultSignersConfig().SequenceRetries
+	}
+	return g, nil
+}
+
+// Pool creates the pool of the given reporter, opening the keys assigned to it that no other pool of the group opened yet
+func (g *Group) Pool(reporter string) (*Pool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	p := &Pool{
+		group:    g,
+		reporter: reporter,
+		logger:   g.logger.With(zap.String("reporter", reporter)).Sugar(),
+	}
+	for _, key := range g.cfg.KeysOf(reporter, g.lorenzoCfg.Key) {
+		s, err := g.signerOf(key)
+		if err != nil {
+			return nil, err
+		}
+		p.signers = append(p.signers, s)
+		p.logger.Infof("Signing with key %s (%s)", key, s.address)
+	}
+	p.Client = p.signers[0].client
+	g.pools = append(g.pools, p)
+
+	return p, nil
+}
+
+// signerOf returns the signer of the given key, opening a Lorenzo client for it on first use
+func (g *Group) signerOf(key string) (*signer, error) {
+	for _, s := range g.signers {
+		if s.key == key {
+			return s, nil
+		}
+	}
+
+	keyCfg := *g.lorenzoCfg
+	keyCfg.Key = key
+	c, err := lrzclient.New(&keyCfg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Lorenzo client for key %s: %w", key, err)
+	}
+	address, err := c.GetAddr()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get address of key %s: %w", key, err)
+	}
+
+	txs, err := newKeyBroadcaster(&keyCfg, address, g.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Lorenzo provider for key %s: %w", key, err)
+	}
+
+	s := &signer{key: key, address: address, client: c, txs: txs}
+	if err := g.syncSequence(g.ctx, s); err != nil {
+		// the account may not exist yet, its first tx will tell
+		g.logger.Sugar().Warnf("Failed to query account sequence of key %s (%s): %v", key, address, err)
+	}
+	g.signers = append(g.signers, s)
+	return s, nil
+}
+
+// MustGetAddr returns the address of the first key. Txs are signed by whichever key is next in turn.
+func (p *Pool) MustGetAddr() string {
+	return p.signers[0].address
+}
+
+// Addresses returns the addresses of all keys of the pool
+func (p *Pool) Addresses() []string {
+	addresses := make([]string, 0, len(p.signers))
+	for _, s := range p.signers {
+		addresses = append(addresses, s.address)
+	}
+	return addresses
+}
+
+// InsertHeaders sends a copy of msg signed by the next key in turn, and returns the address of that key
+func (p *Pool) InsertHeaders(ctx context.Context, msg *btclctypes.MsgInsertHeaders) (*pv.RelayerTxResponse, string, error) {
+	return p.send(ctx, func(s *signer) sdk.Msg {
+		signed := *msg
+		signed.Signer = s.address
+		return &signed
+	})
+}
+
+// BNBUploadHeaders sends a copy of msg signed by the next key in turn, and returns the address of that key
+func (p *Pool) BNBUploadHeaders(ctx context.Context, msg *bnblctypes.MsgUploadHeaders) (*pv.RelayerTxResponse, string, error) {
+	return p.send(ctx, func(s *signer) sdk.Msg {
+		signed := *msg
+		signed.Signer = s.address
+		return &signed
+	})
+}
+
+// Paused returns whether all keys are below the critical balance, in which case no tx can be broadcast
+func (p *Pool) Paused() bool {
+	for _, s := range p.signers {
+		if !s.lowFunds.Load() {
+			return false
+		}
+	}
+	return true
+}
+
+// Start starts the balance monitoring of the group
+func (p *Pool) Start() {
+	p.group.Start()
+}
+
+// Stop stops the balance monitoring and the clients of all keys of the group
+func (p *Pool) Stop() error {
+	return p.group.Stop()
+}
+
+// UpdateConfig applies the balance check settings of the given config to the group
+func (p *Pool) UpdateConfig(cfg *config.SignersConfig) {
+	p.group.UpdateConfig(cfg)
+}
+
+// Stop stops the balance monitoring and the clients of all keys. Only the first call stops them.
+func (g *Group) Stop() error {
+	g.stopOnce.Do(func() {
+		g.cancel()
+		close(g.quit)
+		g.wg.Wait()
+
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		for _, s := range g.signers {
+			if err := s.client.Stop(); err != nil && g.stopErr == nil {
+				g.stopErr = err
+			}
+			if err := s.txs.Stop(); err != nil && g.stopErr == nil {
+				g.stopErr = err
+			}
+		}
+	})
+	return g.stopErr
+}
+
+// send signs the msg built for the next key in turn with the sequence of the key, and returns the address of that key.
+// After an account sequence mismatch the sequence is queried again and the msg is resent with it.
+func (p *Pool) send(ctx context.Context, buildMsg func(s *signer) sdk.Msg) (*pv.RelayerTxResponse, string, error) {
+	s := p.nextSigner()
+	if s == nil {
+		return nil, "", types.ErrSignersPaused
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g := p.group
+	msg := buildMsg(s)
+	for attempt := uint(0); ; attempt++ {
+		res, err := s.txs.Broadcast(ctx, msg, s.sequence)
+		if err == nil {
+			s.sequence++
+			g.metrics.TxsCounterVec.WithLabelValues(s.key, "success").Inc()
+			g.metrics.AccountSequenceGaugeVec.WithLabelValues(s.key).Set(float64(s.sequence))
+			return res, s.address, nil
+		}
+		if !isSequenceMismatch(err) || attempt == g.retries {
+			g.metrics.TxsCounterVec.WithLabelValues(s.key, "failure").Inc()
+			return res, s.address, err
+		}
+
+		g.metrics.SequenceMismatchesCounterVec.WithLabelValues(s.key).Inc()
+		expected := s.sequence
+		if syncErr := g.syncSequence(ctx, s); syncErr != nil {
+			// resending with the same sequence would fail the same way
+			p.logger.Warnf("Failed to query account sequence of key %s after a mismatch: %v", s.key, syncErr)
+			g.metrics.TxsCounterVec.WithLabelValues(s.key, "failure").Inc()
+			return res, s.address, err
+		}
+		p.logger.Warnf("Account sequence mismatch for key %s (signed with %d, on chain %d), resending. Attempt: %d, Max attempts: %d",
+			s.key, expected, s.sequence, attempt+1, g.retries)
+	}
+}
+
+// nextSigner returns the next key in turn that is not below the critical balance
+func (p *Pool) nextSigner() *signer {
+	for range p.signers {
+		s := p.signers[(p.next.Add(1)-1)%uint64(len(p.signers))]
+		if !s.lowFunds.Load() {
+			return s
+		}
+	}
+	return nil
+}
+
+// syncSequence sets the sequence of the key to the one of its account on Lorenzo
+func (g *Group) syncSequence(ctx context.Context, s *signer) error {
+	sequence, err := s.txs.AccountSequence(ctx)
+	if err != nil {
+		return err
+	}
+	s.sequence = sequence
+	g.metrics.AccountSequenceGaugeVec.WithLabelValues(s.key).Set(float64(s.sequence))
+	return nil
+}
+
+func isSequenceMismatch(err error) bool {
+	return strings.Contains(err.Error(), sdkerrors.ErrWrongSequence.Error())
+}
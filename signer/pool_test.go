@@ -0,0 +1,167 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	btclctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	pv "github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// fakeBroadcaster is an account on Lorenzo accepting txs signed with its current sequence
+type fakeBroadcaster struct {
+	chainSequence uint64
+	signedWith    []uint64 // the sequence of every tx broadcast
+	signers       []string // the signer of every tx broadcast
+	queries       int
+}
+
+func (b *fakeBroadcaster) Broadcast(_ context.Context, msg sdk.Msg, sequence uint64) (*pv.RelayerTxResponse, error) {
+	b.signedWith = append(b.signedWith, sequence)
+	b.signers = append(b.signers, msg.(*btclctypes.MsgInsertHeaders).Signer)
+	if sequence != b.chainSequence {
+		return nil, fmt.Errorf("account sequence mismatch, expected %d, got %d: %w", b.chainSequence, sequence, sdkerrors.ErrWrongSequence)
+	}
+	b.chainSequence++
+	return &pv.RelayerTxResponse{}, nil
+}
+
+func (b *fakeBroadcaster) AccountSequence(context.Context) (uint64, error) {
+	b.queries++
+	return b.chainSequence, nil
+}
+
+func (b *fakeBroadcaster) Stop() error { return nil }
+
+func newTestPool(signers ...*signer) *Pool {
+	g := &Group{
+		retries: config.DefaultSignersConfig().SequenceRetries,
+		metrics: metrics.NewSignerMetrics(prometheus.NewRegistry()),
+		logger:  zap.NewNop(),
+		ctx:     context.Background(),
+	}
+	return &Pool{group: g, reporter: config.SignerReporter, signers: signers, logger: zap.NewNop().Sugar()}
+}
+
+func newTestSigner(key string) *signer {
+	return &signer{key: key, address: key + "-address", txs: &fakeBroadcaster{}}
+}
+
+func insertHeaders(t *testing.T, p *Pool) (string, error) {
+	t.Helper()
+	_, address, err := p.InsertHeaders(context.Background(), &btclctypes.MsgInsertHeaders{})
+	return address, err
+}
+
+func TestPoolRotatesKeys(t *testing.T) {
+	p := newTestPool(newTestSigner("key0"), newTestSigner("key1"), newTestSigner("key2"))
+
+	want := []string{"key0-address", "key1-address", "key2-address", "key0-address", "key1-address", "key2-address"}
+	for i, w := range want {
+		address, err := insertHeaders(t, p)
+		if err != nil {
+			t.Fatalf("tx %d: %v", i, err)
+		}
+		if address != w {
+			t.Fatalf("tx %d signed by %s, want %s", i, address, w)
+		}
+	}
+	for _, s := range p.signers {
+		b := s.txs.(*fakeBroadcaster)
+		if s.sequence != 2 || len(b.signedWith) != 2 || b.signedWith[0] != 0 || b.signedWith[1] != 1 {
+			t.Fatalf("key %s signed with %v and tracks sequence %d, want [0 1] and 2", s.key, b.signedWith, s.sequence)
+		}
+		if b.signers[0] != s.address {
+			t.Fatalf("msg of key %s has signer %s", s.key, b.signers[0])
+		}
+	}
+}
+
+func TestPoolSkipsLowFundsKeys(t *testing.T) {
+	p := newTestPool(newTestSigner("key0"), newTestSigner("key1"), newTestSigner("key2"))
+	p.signers[1].lowFunds.Store(true)
+
+	for i, w := range []string{"key0-address", "key2-address", "key0-address", "key2-address"} {
+		address, err := insertHeaders(t, p)
+		if err != nil {
+			t.Fatalf("tx %d: %v", i, err)
+		}
+		if address != w {
+			t.Fatalf("tx %d signed by %s, want %s", i, address, w)
+		}
+	}
+	if n := len(p.signers[1].txs.(*fakeBroadcaster).signedWith); n != 0 {
+		t.Fatalf("low funds key broadcast %d txs", n)
+	}
+}
+
+func TestPoolPausedWhenAllKeysLow(t *testing.T) {
+	p := newTestPool(newTestSigner("key0"), newTestSigner("key1"))
+	for _, s := range p.signers {
+		s.lowFunds.Store(true)
+	}
+
+	if !p.Paused() {
+		t.Fatalf("pool with all keys low is not paused")
+	}
+	if _, err := insertHeaders(t, p); !errors.Is(err, types.ErrSignersPaused) {
+		t.Fatalf("got %v, want %v", err, types.ErrSignersPaused)
+	}
+}
+
+func TestPoolResendsWithQueriedSequence(t *testing.T) {
+	s := newTestSigner("key0")
+	s.sequence = 3
+	b := s.txs.(*fakeBroadcaster)
+	b.chainSequence = 5 // another process sent two txs with the key
+	p := newTestPool(s)
+
+	if _, err := insertHeaders(t, p); err != nil {
+		t.Fatalf("tx failed after a sequence mismatch: %v", err)
+	}
+	if b.queries != 1 {
+		t.Fatalf("sequence queried %d times, want 1", b.queries)
+	}
+	if len(b.signedWith) != 2 || b.signedWith[0] != 3 || b.signedWith[1] != 5 {
+		t.Fatalf("txs signed with %v, want [3 5]", b.signedWith)
+	}
+	if s.sequence != 6 {
+		t.Fatalf("sequence is %d after the tx, want 6", s.sequence)
+	}
+}
+
+func TestPoolGivesUpAfterRetries(t *testing.T) {
+	s := newTestSigner("key0")
+	p := newTestPool(s)
+	p.group.retries = 2
+	s.txs = &racingBroadcaster{}
+
+	if _, err := insertHeaders(t, p); !isSequenceMismatch(err) {
+		t.Fatalf("got %v, want a sequence mismatch", err)
+	}
+	if n := s.txs.(*racingBroadcaster).broadcasts; n != 3 {
+		t.Fatalf("tx broadcast %d times, want 3", n)
+	}
+}
+
+// racingBroadcaster is an account whose sequence is always taken by another process first
+type racingBroadcaster struct {
+	fakeBroadcaster
+	broadcasts int
+}
+
+func (b *racingBroadcaster) Broadcast(ctx context.Context, msg sdk.Msg, sequence uint64) (*pv.RelayerTxResponse, error) {
+	b.broadcasts++
+	b.chainSequence++
+	return b.fakeBroadcaster.Broadcast(ctx, msg, sequence)
+}
@@ -0,0 +1,72 @@
+package signer
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	bnblctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/bnblightclient/types"
+	btclctypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/cosmos/cosmos-sdk/client"
+)
+
+// The queries of the SDK client take no context. The ones used by the reporters are redone below, bounded by
+// the caller's context and the timeout of the Lorenzo config.
+
+// queryContext bounds a query by the given context and the timeout of the Lorenzo config
+func (p *Pool) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, p.GetConfig().Timeout)
+}
+
+func (p *Pool) clientContext() client.Context {
+	return client.Context{Client: p.RPCClient}
+}
+
+// BTCHeaderChainTip queries the hash and height of the latest BTC header in the btclightclient module
+func (p *Pool) BTCHeaderChainTip(ctx context.Context) (*btclctypes.QueryTipResponse, error) {
+	ctx, cancel := p.queryContext(ctx)
+	defer cancel()
+	return btclctypes.NewQueryClient(p.clientContext()).Tip(ctx, &btclctypes.QueryTipRequest{})
+}
+
+// BTCBaseHeader queries the base BTC header of the btclightclient module
+func (p *Pool) BTCBaseHeader(ctx context.Context) (*btclctypes.QueryBaseHeaderResponse, error) {
+	ctx, cancel := p.queryContext(ctx)
+	defer cancel()
+	return btclctypes.NewQueryClient(p.clientContext()).BaseHeader(ctx, &btclctypes.QueryBaseHeaderRequest{})
+}
+
+// ContainsBTCBlock queries whether the btclightclient module contains the given BTC block
+func (p *Pool) ContainsBTCBlock(ctx context.Context, blockHash *chainhash.Hash) (*btclctypes.QueryContainsBytesResponse, error) {
+	ctx, cancel := p.queryContext(ctx)
+	defer cancel()
+	req := &btclctypes.QueryContainsBytesRequest{Hash: blockHash.CloneBytes()}
+	return btclctypes.NewQueryClient(p.clientContext()).ContainsBytes(ctx, req)
+}
+
+// BNBLatestHeader queries the latest BNB header of the bnblightclient module
+func (p *Pool) BNBLatestHeader(ctx context.Context) (*bnblctypes.Header, error) {
+	ctx, cancel := p.queryContext(ctx)
+	defer cancel()
+	res, err := bnblctypes.NewQueryClient(p.clientContext()).LatestHeader(ctx, &bnblctypes.QueryLatestHeaderRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return &res.Header, nil
+}
+
+// TxGasUsed queries the gas used by the committed tx with the given hash
+func (p *Pool) TxGasUsed(ctx context.Context, txHash string) (int64, error) {
+	hash, err := hex.DecodeString(txHash)
+	if err != nil {
+		return 0, fmt.Errorf("invalid tx hash %q: %w", txHash, err)
+	}
+	ctx, cancel := p.queryContext(ctx)
+	defer cancel()
+	res, err := p.RPCClient.Tx(ctx, hash, false)
+	if err != nil {
+		return 0, err
+	}
+	return res.TxResult.GasUsed, nil
+}
@@ -0,0 +1,190 @@
+// Package supervisor runs the reporters of a process as services, restarting a failed service with
+// exponential backoff and escalating to the process only under the configured policy.
+package supervisor
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// Service is a component run by the supervisor. It reports the failures of its goroutines to the failure
+// handler instead of crashing the process, and Start returns the failures of starting.
+type Service interface {
+	SetFailureHandler(handler func(error))
+	Start() error
+	Stop()
+	WaitForShutdown()
+}
+
+// Factory creates the service; it is called again for every restart, so that no state of a failed
+// instance is reused
+type Factory func() (Service, error)
+
+type service struct {
+	name    string
+	factory Factory
+}
+
+// Supervisor runs services and restarts the failed ones with exponential backoff and jitter
+type Supervisor struct {
+	cfg     *config.SupervisorConfig
+	logger  *zap.SugaredLogger
+	metrics *metrics.SupervisorMetrics
+
+	services     []service
+	escalated    chan error
+	escalateOnce sync.Once
+	stopOnce     sync.Once
+	wg           sync.WaitGroup
+	quit         chan struct{}
+}
+
+func New(cfg *config.SupervisorConfig, parentLogger *zap.Logger, metrics *metrics.SupervisorMetrics) *Supervisor {
+	return &Supervisor{
+		cfg:       cfg,
+		logger:    parentLogger.With(zap.String("module", "supervisor")).Sugar(),
+		metrics:   metrics,
+		escalated: make(chan error, 1),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Add adds a service, to be called before Start
+func (s *Supervisor) Add(name string, factory Factory) {
+	s.services = append(s.services, service{name: name, factory: factory})
+}
+
+// Start runs every service in its own goroutine
+func (s *Supervisor) Start() {
+	for _, svc := range s.services {
+		s.metrics.RestartsCounterVec.WithLabelValues(svc.name)
+		s.wg.Add(1)
+		go s.supervise(svc)
+	}
+}
+
+// Stop stops the running services and waits for them to shut down
+func (s *Supervisor) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.quit)
+	})
+	s.wg.Wait()
+}
+
+// Escalated receives the failure on which the process has to exit, at most once. The failed service is not
+// restarted, the others keep running until Stop.
+func (s *Supervisor) Escalated() <-chan error {
+	return s.escalated
+}
+
+func (s *Supervisor) supervise(svc service) {
+	defer s.wg.Done()
+
+	failures := 0
+	for {
+		started := time.Now()
+		err := s.run(svc)
+		if err == nil {
+			return
+		}
+
+		class := types.ClassOf(err)
+		s.metrics.FailuresCounterVec.WithLabelValues(svc.name, class).Inc()
+		if s.cfg.RestartWindow > 0 && time.Since(started) >= s.cfg.RestartWindow {
+			failures = 0
+		}
+		failures++
+
+		if slices.Contains(s.cfg.ExitOn, class) {
+			s.escalate(fmt.Errorf("%s: %w", svc.name, err))
+			return
+		}
+		if s.cfg.MaxRestarts > 0 && failures > s.cfg.MaxRestarts {
+			s.escalate(fmt.Errorf("%s failed %d times in a row: %w", svc.name, failures, err))
+			return
+		}
+
+		delay := s.backoff(failures)
+		s.logger.Errorf("%s failed with a %s error, restarting it in %v (failure %d in a row): %v",
+			svc.name, class, delay, failures, err)
+		select {
+		case <-time.After(delay):
+		case <-s.quit:
+			return
+		}
+		s.metrics.RestartsCounterVec.WithLabelValues(svc.name).Inc()
+		s.logger.Infof("Restarting %s", svc.name)
+	}
+}
+
+// run creates and starts the service, and blocks until it fails or the supervisor stops.
+// It returns the failure, or nil if the supervisor stopped.
+func (s *Supervisor) run(svc service) error {
+	instance, err := svc.factory()
+	if err != nil {
+		return fmt.Errorf("failed to create: %w", err)
+	}
+
+	failed := make(chan error, 1)
+	fail := func(err error) {
+		select {
+		case failed <- err:
+		default:
+		}
+	}
+	instance.SetFailureHandler(fail)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				fail(fmt.Errorf("failed to start: %w", types.PanicError(rec)))
+			}
+		}()
+		if err := instance.Start(); err != nil {
+			fail(fmt.Errorf("failed to start: %w", err))
+		}
+	}()
+	s.metrics.UpGaugeVec.WithLabelValues(svc.name).Set(1)
+	defer s.metrics.UpGaugeVec.WithLabelValues(svc.name).Set(0)
+
+	select {
+	case err = <-failed:
+		s.logger.Errorf("Stopping %s after a failure: %v", svc.name, err)
+	case <-s.quit:
+		s.logger.Infof("Stopping %s...", svc.name)
+	}
+	instance.Stop()
+	instance.WaitForShutdown()
+	s.logger.Infof("%s shutdown", svc.name)
+	return err
+}
+
+// backoff returns the delay before the restart following the given number of consecutive failures
+func (s *Supervisor) backoff(failures int) time.Duration {
+	delay := s.cfg.InitialBackoff
+	for i := 1; i < failures && delay < s.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.cfg.MaxBackoff {
+		delay = s.cfg.MaxBackoff
+	}
+	if s.cfg.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * s.cfg.Jitter * float64(delay))
+	}
+	return delay
+}
+
+func (s *Supervisor) escalate(err error) {
+	s.escalateOnce.Do(func() {
+		s.logger.Errorf("Not restarting after the failure, exiting: %v", err)
+		s.escalated <- err
+	})
+}
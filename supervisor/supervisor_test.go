@@ -0,0 +1,111 @@
+package supervisor_test
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/metrics"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/supervisor"
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// failingService fails right after starting with the given error, or returns it from Start if failsStart is set
+type failingService struct {
+	err        error
+	failsStart bool
+	onFail     func(error)
+	stopped    atomic.Bool
+}
+
+func (s *failingService) SetFailureHandler(handler func(error)) { s.onFail = handler }
+
+func (s *failingService) Start() error {
+	if s.failsStart {
+		return s.err
+	}
+	go s.onFail(s.err)
+	return nil
+}
+
+func (s *failingService) Stop()            { s.stopped.Store(true) }
+func (s *failingService) WaitForShutdown() {}
+
+func testConfig() *config.SupervisorConfig {
+	cfg := config.DefaultSupervisorConfig()
+	cfg.InitialBackoff = time.Millisecond
+	cfg.MaxBackoff = 4 * time.Millisecond
+	cfg.MaxRestarts = 3
+	return &cfg
+}
+
+func TestSupervisorRestartsUntilMaxRestarts(t *testing.T) {
+	m := metrics.NewSupervisorMetrics(prometheus.NewRegistry())
+	sup := supervisor.New(testConfig(), zap.NewNop(), m)
+
+	var created []*failingService
+	sup.Add("reporter", func() (supervisor.Service, error) {
+		s := &failingService{err: fmt.Errorf("%w: node down", types.ErrSourceChainUnavailable), failsStart: len(created)%2 == 0}
+		created = append(created, s)
+		return s, nil
+	})
+	sup.Start()
+	defer sup.Stop()
+
+	select {
+	case err := <-sup.Escalated():
+		if !errors.Is(err, types.ErrSourceChainUnavailable) {
+			t.Fatalf("escalated error %v lost its class", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not give up after max-restarts")
+	}
+
+	// the first run and max-restarts restarts
+	if len(created) != 4 {
+		t.Fatalf("service created %d times, expected 4", len(created))
+	}
+	for i, s := range created {
+		if !s.stopped.Load() {
+			t.Errorf("failed instance %d was not stopped", i)
+		}
+	}
+	if restarts := testutil.ToFloat64(m.RestartsCounterVec.WithLabelValues("reporter")); restarts != 3 {
+		t.Errorf("restarts metric is %v, expected 3", restarts)
+	}
+	failures := testutil.ToFloat64(m.FailuresCounterVec.WithLabelValues("reporter", types.ClassSourceChainUnavailable))
+	if failures != 4 {
+		t.Errorf("failures metric is %v, expected 4", failures)
+	}
+}
+
+func TestSupervisorExitsOnClass(t *testing.T) {
+	sup := supervisor.New(testConfig(), zap.NewNop(), metrics.NewSupervisorMetrics(prometheus.NewRegistry()))
+
+	var attempts atomic.Int32
+	sup.Add("bnbreporter", func() (supervisor.Service, error) {
+		attempts.Add(1)
+		return nil, fmt.Errorf("%w: missing rpc_url", types.ErrConfig)
+	})
+	sup.Start()
+	defer sup.Stop()
+
+	select {
+	case err := <-sup.Escalated():
+		if types.ExitCode(err) != types.ExitConfig {
+			t.Fatalf("escalated error %v exits with %d", err, types.ExitCode(err))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not exit on a config error")
+	}
+	if n := attempts.Load(); n != 1 {
+		t.Errorf("service created %d times, expected no restart", n)
+	}
+}
@@ -0,0 +1,84 @@
+// Package tracing exports spans around the stages of the reporters to an OTLP collector.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/config"
+)
+
+const (
+	serviceName         = "lrzrelayer"
+	instrumentationName = "github.com/Lorenzo-Protocol/lorenzo-relayer/v2"
+)
+
+// Attributes of the reporters' spans
+const (
+	HeightKey      = attribute.Key("lrzrelayer.height")
+	HashKey        = attribute.Key("lrzrelayer.hash")
+	TipKey         = attribute.Key("lrzrelayer.tip")
+	FirstHeightKey = attribute.Key("lrzrelayer.first_height")
+	LastHeightKey  = attribute.Key("lrzrelayer.last_height")
+	HeadersKey     = attribute.Key("lrzrelayer.headers")
+	EventKey       = attribute.Key("lrzrelayer.event")
+	TxHashKey      = attribute.Key("lrzrelayer.tx_hash")
+	TxCodeKey      = attribute.Key("lrzrelayer.tx_code")
+)
+
+// Tracer returns the tracer of a reporter. Its spans are dropped until Start installs an exporter.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(instrumentationName + "/" + name)
+}
+
+// End records err, if any, on the span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Start installs a tracer provider exporting the reporters' spans over OTLP/HTTP, if tracing is enabled.
+// The returned function flushes the pending spans and stops the export.
+func Start(cfg *config.TracingConfig, parentLogger *zap.Logger) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	// the exporter connects lazily, so an unreachable collector does not keep the reporters from starting
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	logger := parentLogger.With(zap.String("module", "tracing")).Sugar()
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warnf("Failed to export spans: %v", err)
+	}))
+	logger.Infof("Exporting spans to %s", cfg.Endpoint)
+
+	return provider.Shutdown, nil
+}
@@ -14,6 +14,16 @@ const (
 	BlockConnected
 )
 
+func (t EventType) String() string {
+	switch t {
+	case BlockDisconnected:
+		return "disconnected"
+	case BlockConnected:
+		return "connected"
+	}
+	return "unknown"
+}
+
 type BlockEvent struct {
 	EventType EventType
 	Height    int32
@@ -4,33 +4,37 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 )
 
+// BTCCache keeps the latest BTC blocks at consecutive heights in a ring buffer,
+// so that blocks are found by their offset from the first block.
 type BTCCache struct {
-	blocks     []*IndexedBlock
-	maxEntries uint64
+	blocks     []*IndexedBlock // ring buffer, blocks[head] is the first block
+	head       int
+	count      int
+	maxEntries uint64 // 0 means the cache is unbounded
+	byHash     map[chainhash.Hash]*IndexedBlock
 
 	sync.RWMutex
 }
 
+// NewBTCCache creates a cache keeping at most maxEntries blocks. If maxEntries is 0, the cache is unbounded.
 func NewBTCCache(maxEntries uint64) (*BTCCache, error) {
-	// if maxEntries is 0, it means that the cache is disabled
-	if maxEntries == 0 {
-		return nil, ErrInvalidMaxEntries
-	}
-
 	return &BTCCache{
-		blocks:     make([]*IndexedBlock, 0, maxEntries),
+		blocks:     make([]*IndexedBlock, maxEntries),
 		maxEntries: maxEntries,
+		byHash:     make(map[chainhash.Hash]*IndexedBlock, maxEntries),
 	}, nil
 }
 
-// Init initializes the cache with the given blocks. Input blocks should be sorted by height. Thread-safe.
+// Init initializes the cache with the given blocks. Input blocks should be sorted by consecutive heights. Thread-safe.
 func (b *BTCCache) Init(ibs []*IndexedBlock) error {
 	b.Lock()
 	defer b.Unlock()
 
-	if len(ibs) > int(b.maxEntries) {
+	if b.maxEntries > 0 && len(ibs) > int(b.maxEntries) {
 		return ErrTooManyEntries
 	}
 
@@ -40,6 +44,11 @@ func (b *BTCCache) Init(ibs []*IndexedBlock) error {
 	}); !sortedByHeight {
 		return ErrorUnsortedBlocks
 	}
+	for i := 1; i < len(ibs); i++ {
+		if ibs[i].Height != ibs[i-1].Height+1 {
+			return ErrNonConsecutiveBlocks
+		}
+	}
 
 	for _, ib := range ibs {
 		b.add(ib)
@@ -48,7 +57,7 @@ func (b *BTCCache) Init(ibs []*IndexedBlock) error {
 	return nil
 }
 
-// Add adds a new block to the cache. Thread-safe.
+// Add adds a new block to the cache, evicting the first block if the cache is full. Thread-safe.
 func (b *BTCCache) Add(ib *IndexedBlock) {
 	b.Lock()
 	defer b.Unlock()
@@ -58,17 +67,55 @@ func (b *BTCCache) Add(ib *IndexedBlock) {
 
 // Thread-unsafe version of Add
 func (b *BTCCache) add(ib *IndexedBlock) {
-	if b.size() > b.maxEntries {
-		panic(ErrTooManyEntries)
+	if b.maxEntries > 0 {
+		for b.size() >= b.maxEntries {
+			b.removeFirst()
+		}
+	}
+	if b.count == len(b.blocks) {
+		b.grow()
+	}
+
+	b.blocks[(b.head+b.count)%len(b.blocks)] = ib
+	b.count++
+	b.byHash[ib.BlockHash()] = ib
+}
+
+// grow doubles the capacity of the ring buffer, up to maxEntries if the cache is bounded
+func (b *BTCCache) grow() {
+	capacity := 2 * len(b.blocks)
+	if capacity == 0 {
+		capacity = 1
 	}
-	if b.size() == b.maxEntries {
-		// dereference the 0-th block to ensure it will be garbage-collected
-		// see https://stackoverflow.com/questions/55045402/memory-leak-in-golang-slice
-		b.blocks[0] = nil
-		b.blocks = b.blocks[1:]
+	if b.maxEntries > 0 && capacity > int(b.maxEntries) {
+		capacity = int(b.maxEntries)
 	}
+	if capacity <= b.count {
+		capacity = b.count + 1
+	}
+
+	blocks := make([]*IndexedBlock, capacity)
+	for i := 0; i < b.count; i++ {
+		blocks[i] = b.at(i)
+	}
+	b.blocks = blocks
+	b.head = 0
+}
+
+// at returns the i-th block from the first block in cache
+func (b *BTCCache) at(i int) *IndexedBlock {
+	return b.blocks[(b.head+i)%len(b.blocks)]
+}
 
-	b.blocks = append(b.blocks, ib)
+// removeFirst deletes the first block in cache
+func (b *BTCCache) removeFirst() *IndexedBlock {
+	ib := b.blocks[b.head]
+	// dereference the block to ensure it will be garbage-collected
+	b.blocks[b.head] = nil
+	b.head = (b.head + 1) % len(b.blocks)
+	b.count--
+	delete(b.byHash, ib.BlockHash())
+	return ib
 }
 
 func (b *BTCCache) First() *IndexedBlock {
@@ -79,7 +126,7 @@ func (b *BTCCache) First() *IndexedBlock {
 		return nil
 	}
 
-	return b.blocks[0]
+	return b.at(0)
 }
 
 func (b *BTCCache) Tip() *IndexedBlock {
@@ -90,7 +137,7 @@ func (b *BTCCache) Tip() *IndexedBlock {
 		return nil
 	}
 
-	return b.blocks[len(b.blocks)-1]
+	return b.at(b.count - 1)
 }
 
 // RemoveLast deletes the last block in cache
@@ -103,8 +150,10 @@ func (b *BTCCache) RemoveLast() error {
 	}
 
 	// dereference the last block to ensure it will be garbage-collected
-	b.blocks[len(b.blocks)-1] = nil
-	b.blocks = b.blocks[:len(b.blocks)-1]
+	last := (b.head + b.count - 1) % len(b.blocks)
+	delete(b.byHash, b.blocks[last].BlockHash())
+	b.blocks[last] = nil
+	b.count--
 	return nil
 }
 
@@ -113,7 +162,10 @@ func (b *BTCCache) RemoveAll() {
 	b.Lock()
 	defer b.Unlock()
 
-	b.blocks = []*IndexedBlock{}
+	b.blocks = make([]*IndexedBlock, b.maxEntries)
+	b.head = 0
+	b.count = 0
+	b.byHash = make(map[chainhash.Hash]*IndexedBlock, b.maxEntries)
 }
 
 // Size returns the size of the cache. Thread-safe.
@@ -126,37 +178,54 @@ func (b *BTCCache) Size() uint64 {
 
 // thread-unsafe version of Size
 func (b *BTCCache) size() uint64 {
-	return uint64(len(b.blocks))
+	return uint64(b.count)
 }
 
-// GetLastBlocks returns list of blocks between the given stopHeight and the tip of the chain in cache
+// index returns the position of the block with the given height from the first block, or -1 if it is not in cache
+func (b *BTCCache) index(blockHeight uint64) int {
+	if b.count == 0 {
+		return -1
+	}
+	i := int64(blockHeight) - int64(b.at(0).Height)
+	if i < 0 || i >= int64(b.count) || b.at(int(i)).Height != int32(blockHeight) {
+		return -1
+	}
+	return int(i)
+}
+
+// blocksFrom returns a copy of the blocks from the i-th one to the tip
+func (b *BTCCache) blocksFrom(i int) []*IndexedBlock {
+	ibs := make([]*IndexedBlock, 0, b.count-i)
+	for ; i < b.count; i++ {
+		ibs = append(ibs, b.at(i))
+	}
+	return ibs
+}
+
+// GetLastBlocks returns a copy of the blocks between the given stopHeight and the tip of the chain in cache
 func (b *BTCCache) GetLastBlocks(stopHeight uint64) ([]*IndexedBlock, error) {
 	b.RLock()
 	defer b.RUnlock()
 
-	firstHeight := b.blocks[0].Height
-	lastHeight := b.blocks[len(b.blocks)-1].Height
-	if int32(stopHeight) < firstHeight || lastHeight < int32(stopHeight) {
-		return []*IndexedBlock{}, fmt.Errorf("the given stopHeight %d is out of range [%d, %d] of BTC cache", stopHeight, firstHeight, lastHeight)
+	if b.size() == 0 {
+		return []*IndexedBlock{}, ErrEmptyCache
 	}
-
-	var j int
-	for i := len(b.blocks) - 1; i >= 0; i-- {
-		if b.blocks[i].Height == int32(stopHeight) {
-			j = i
-			break
-		}
+	i := b.index(stopHeight)
+	if i < 0 {
+		firstHeight := b.at(0).Height
+		lastHeight := b.at(b.count - 1).Height
+		return []*IndexedBlock{}, fmt.Errorf("the given stopHeight %d is out of range [%d, %d] of BTC cache", stopHeight, firstHeight, lastHeight)
 	}
 
-	return b.blocks[j:], nil
+	return b.blocksFrom(i), nil
 }
 
-// GetAllBlocks returns list of all blocks in cache
+// GetAllBlocks returns a copy of all blocks in cache
 func (b *BTCCache) GetAllBlocks() []*IndexedBlock {
 	b.RLock()
 	defer b.RUnlock()
 
-	return b.blocks
+	return b.blocksFrom(0)
 }
 
 // TrimConfirmedBlocks keeps the last <=k blocks in the cache and returns the rest in the same order
@@ -165,74 +234,69 @@ func (b *BTCCache) TrimConfirmedBlocks(k int) []*IndexedBlock {
 	b.Lock()
 	defer b.Unlock()
 
-	l := len(b.blocks)
-	if l <= k {
+	if b.count <= k {
 		return nil
 	}
 
-	res := make([]*IndexedBlock, l-k)
-	copy(res, b.blocks)
-	b.blocks = b.blocks[l-k:]
+	res := make([]*IndexedBlock, 0, b.count-k)
+	for b.count > k {
+		res = append(res, b.removeFirst())
+	}
 
 	return res
 }
 
-// FindBlock uses binary search to find the block with the given height in cache
+// FindBlock returns the block with the given height in cache, or nil if it is not in cache
 func (b *BTCCache) FindBlock(blockHeight uint64) *IndexedBlock {
 	b.RLock()
 	defer b.RUnlock()
 
-	firstHeight := b.blocks[0].Height
-	lastHeight := b.blocks[len(b.blocks)-1].Height
-	if int32(blockHeight) < firstHeight || lastHeight < int32(blockHeight) {
+	i := b.index(blockHeight)
+	if i < 0 {
 		return nil
 	}
+	return b.at(i)
+}
 
-	leftBound := uint64(0)
-	rightBound := b.size() - 1
-
-	for leftBound <= rightBound {
-		midPoint := leftBound + (rightBound-leftBound)/2
-
-		if b.blocks[midPoint].Height == int32(blockHeight) {
-			return b.blocks[midPoint]
-		}
-
-		if b.blocks[midPoint].Height > int32(blockHeight) {
-			rightBound = midPoint - 1
-		} else {
-			leftBound = midPoint + 1
-		}
-	}
+// FindBlockByHash returns the block with the given hash in cache, or nil if it is not in cache
+func (b *BTCCache) FindBlockByHash(hash *chainhash.Hash) *IndexedBlock {
+	b.RLock()
+	defer b.RUnlock()
 
-	return nil
+	return b.byHash[*hash]
 }
 
+// Resize sets the maximum number of blocks in cache, 0 makes the cache unbounded.
+// Blocks exceeding the new size are evicted on Trim or on the next Add.
 func (b *BTCCache) Resize(maxEntries uint64) error {
 	b.Lock()
 	defer b.Unlock()
 
-	if maxEntries == 0 {
-		return ErrInvalidMaxEntries
-	}
 	b.maxEntries = maxEntries
 	return nil
 }
 
-// Trim trims BTCCache to only keep the latest `maxEntries` blocks, and set `maxEntries` to be the cache size
+// Trim trims BTCCache to only keep the latest `maxEntries` blocks
 func (b *BTCCache) Trim() {
 	b.Lock()
 	defer b.Unlock()
 
-	// cache size is smaller than maxEntries, can't trim
-	if b.size() < b.maxEntries {
+	// the cache is unbounded or smaller than maxEntries, can't trim
+	if b.maxEntries == 0 || b.size() <= b.maxEntries {
 		return
 	}
 
-	// dereference b.blocks[:len(b.blocks)-int(b.maxEntries)] to ensure they will be garbage-collected
-	for i := range b.blocks[:len(b.blocks)-int(b.maxEntries)] {
-		b.blocks[i] = nil
+	for b.size() > b.maxEntries {
+		b.removeFirst()
 	}
 
-	b.blocks = b.blocks[len(b.blocks)-int(b.maxEntries):]
+	// release the memory of a buffer grown while the cache was larger
+	if len(b.blocks) > int(b.maxEntries) {
+		blocks := make([]*IndexedBlock, b.maxEntries)
+		for i := 0; i < b.count; i++ {
+			blocks[i] = b.at(i)
+		}
+		b.blocks = blocks
+		b.head = 0
+	}
 }
@@ -0,0 +1,76 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+func TestBTCCache(t *testing.T) {
+	genesis := types.NewIndexedBlock(10, wire.NewBlockHeader(1, &chainhash.Hash{}, &chainhash.Hash{}, 0x1d00ffff, 0), nil)
+	chain := append([]*types.IndexedBlock{genesis}, extend(genesis, 9, 0x1d00ffff, 1)...)
+
+	cache, err := types.NewBTCCache(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Init(chain[:5]); err != types.ErrTooManyEntries {
+		t.Fatalf("expected ErrTooManyEntries, got %v", err)
+	}
+	if err := cache.Init([]*types.IndexedBlock{chain[0], chain[2]}); err != types.ErrNonConsecutiveBlocks {
+		t.Fatalf("expected ErrNonConsecutiveBlocks, got %v", err)
+	}
+
+	// the ring buffer wraps around several times
+	for _, ib := range chain {
+		cache.Add(ib)
+	}
+	if cache.Size() != 4 || cache.First() != chain[6] || cache.Tip() != chain[9] {
+		t.Fatalf("unexpected cache of size %d", cache.Size())
+	}
+	if cache.FindBlock(17) != chain[7] || cache.FindBlock(15) != nil || cache.FindBlock(20) != nil {
+		t.Fatal("unexpected blocks found by height")
+	}
+	evicted := chain[5].BlockHash()
+	if hash := chain[8].BlockHash(); cache.FindBlockByHash(&hash) != chain[8] || cache.FindBlockByHash(&evicted) != nil {
+		t.Fatal("unexpected blocks found by hash")
+	}
+
+	// returned blocks do not alias the cache
+	last, err := cache.GetLastBlocks(18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Add(extend(chain[9], 1, 0x1d00ffff, 1)[0])
+	if len(last) != 2 || last[0] != chain[8] || last[1] != chain[9] {
+		t.Fatal("blocks returned by GetLastBlocks changed after Add")
+	}
+	if _, err := cache.GetLastBlocks(16); err == nil {
+		t.Fatal("expected an error for a height below the cache")
+	}
+
+	// an unbounded cache keeps every block until it is resized and trimmed
+	unbounded, err := types.NewBTCCache(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unbounded.Init(chain); err != nil {
+		t.Fatal(err)
+	}
+	if err := unbounded.RemoveLast(); err != nil {
+		t.Fatal(err)
+	}
+	if unbounded.Size() != 9 || unbounded.Tip() != chain[8] {
+		t.Fatalf("unexpected unbounded cache of size %d", unbounded.Size())
+	}
+	if err := unbounded.Resize(3); err != nil {
+		t.Fatal(err)
+	}
+	unbounded.Trim()
+	if all := unbounded.GetAllBlocks(); len(all) != 3 || all[0] != chain[6] || unbounded.FindBlock(16) != chain[6] {
+		t.Fatal("unexpected blocks after trimming")
+	}
+}
@@ -1,10 +1,32 @@
 package types
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
+
+// Error classes of the failures the commands exit on. Errors are classified by wrapping one of them,
+// and each class has its own exit code, see ExitCode.
+var (
+	ErrConfig                 = errors.New("invalid configuration")
+	ErrSourceChainUnavailable = errors.New("source chain is unavailable")
+	ErrLorenzoUnavailable     = errors.New("Lorenzo is unavailable")
+	ErrChainInconsistency     = errors.New("source chain is inconsistent with Lorenzo")
+	ErrInsufficientFunds      = errors.New("insufficient funds")
+)
+
+// ErrReplayDivergence is returned by the replay command when the replayed submissions differ from the journaled ones
+var ErrReplayDivergence = errors.New("replay diverged from the journal")
 
 var (
 	ErrEmptyCache        = errors.New("empty cache")
 	ErrInvalidMaxEntries = errors.New("invalid max entries")
 	ErrTooManyEntries    = errors.New("the number of blocks is more than maxEntries")
 	ErrorUnsortedBlocks  = errors.New("blocks are not sorted by height")
+	ErrUnknownParent     = errors.New("parent block is not in the header tree")
+
+	ErrNonConsecutiveBlocks = errors.New("blocks are not at consecutive heights")
+
+	ErrCheckpointMismatch = fmt.Errorf("%w: source chain disagrees with trusted checkpoint", ErrChainInconsistency)
+	ErrSignersPaused      = fmt.Errorf("%w: all Lorenzo signers are below the critical balance", ErrInsufficientFunds)
 )
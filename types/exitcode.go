@@ -0,0 +1,88 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit codes of the commands, by error class. 2 is left out as the Go runtime exits with it on unrecovered panics.
+const (
+	ExitOK                     = 0
+	ExitFailure                = 1 // errors of no class
+	ExitConfig                 = 3
+	ExitSourceChainUnavailable = 4
+	ExitLorenzoUnavailable     = 5
+	ExitChainInconsistency     = 6
+	ExitInsufficientFunds      = 7
+	ExitReplayDivergence       = 8
+)
+
+// Names of the error classes, e.g., for configs and metric labels
+const (
+	ClassUnknown                = "unknown"
+	ClassConfig                 = "config"
+	ClassSourceChainUnavailable = "source-chain"
+	ClassLorenzoUnavailable     = "lorenzo"
+	ClassChainInconsistency     = "inconsistency"
+	ClassInsufficientFunds      = "insufficient-funds"
+)
+
+// errorClasses are checked in order, so that an error wrapping several classes gets the most specific one
+var errorClasses = []struct {
+	class error
+	name  string
+	code  int
+}{
+	{ErrConfig, ClassConfig, ExitConfig},
+	{ErrChainInconsistency, ClassChainInconsistency, ExitChainInconsistency},
+	{ErrInsufficientFunds, ClassInsufficientFunds, ExitInsufficientFunds},
+	{ErrLorenzoUnavailable, ClassLorenzoUnavailable, ExitLorenzoUnavailable},
+	{ErrSourceChainUnavailable, ClassSourceChainUnavailable, ExitSourceChainUnavailable},
+}
+
+// ExitCode returns the exit code of the class of the given error
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	if errors.Is(err, ErrReplayDivergence) {
+		return ExitReplayDivergence
+	}
+	for _, c := range errorClasses {
+		if errors.Is(err, c.class) {
+			return c.code
+		}
+	}
+	return ExitFailure
+}
+
+// ClassOf returns the name of the class of the given error, ClassUnknown if it has none
+func ClassOf(err error) string {
+	for _, c := range errorClasses {
+		if errors.Is(err, c.class) {
+			return c.name
+		}
+	}
+	return ClassUnknown
+}
+
+// IsClass returns whether the given name is the name of an error class
+func IsClass(name string) bool {
+	if name == ClassUnknown {
+		return true
+	}
+	for _, c := range errorClasses {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PanicError returns the error a recovered panic was raised with, keeping its class
+func PanicError(rec interface{}) error {
+	if err, ok := rec.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", rec)
+}
@@ -0,0 +1,34 @@
+package types_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+func TestExitCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		code int
+	}{
+		{nil, types.ExitOK},
+		{errors.New("unclassified"), types.ExitFailure},
+		{fmt.Errorf("%w: wrong network", types.ErrConfig), types.ExitConfig},
+		{fmt.Errorf("failed to get BTC tip: %w: %w", types.ErrSourceChainUnavailable, errors.New("EOF")), types.ExitSourceChainUnavailable},
+		{fmt.Errorf("failed to get Lorenzo tip: %w", types.ErrLorenzoUnavailable), types.ExitLorenzoUnavailable},
+		{fmt.Errorf("%w: BTC block at height 1", types.ErrCheckpointMismatch), types.ExitChainInconsistency},
+		{types.ErrSignersPaused, types.ExitInsufficientFunds},
+		{fmt.Errorf("%w: 2 block events", types.ErrReplayDivergence), types.ExitReplayDivergence},
+		// the most specific class wins
+		{errors.Join(types.ErrSourceChainUnavailable, types.ErrChainInconsistency), types.ExitChainInconsistency},
+		{types.PanicError(fmt.Errorf("bootstrap: %w", types.ErrLorenzoUnavailable)), types.ExitLorenzoUnavailable},
+		{types.PanicError("boom"), types.ExitFailure},
+	}
+	for _, c := range cases {
+		if code := types.ExitCode(c.err); code != c.code {
+			t.Errorf("expected exit code %d for %v, got %d", c.code, c.err, code)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package types
+
+import (
+	"fmt"
+	"sync"
+
+	sdkmath "cosmossdk.io/math"
+	btcltypes "github.com/Lorenzo-Protocol/lorenzo/v3/x/btclightclient/types"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+type headerNode struct {
+	block  *IndexedBlock
+	parent *headerNode // nil for the root
+	work   sdkmath.Uint
+	// disconnected is set when the BTC node reports the block as disconnected,
+	// which excludes it and its descendants from the best chain
+	disconnected bool
+}
+
+// HeaderTree is a tree of BTC headers keyed by hash. It tracks competing branches with their cumulative work,
+// follows the branch with the most work as the best chain, and forgets blocks below the finality depth.
+type HeaderTree struct {
+	nodes map[chainhash.Hash]*headerNode
+	root  *headerNode
+	best  []*headerNode // best chain from the root, best[i] has height root.Height+i
+
+	sync.RWMutex
+}
+
+// NewHeaderTree creates a tree from a chain of blocks sorted by height. The first block becomes the root.
+func NewHeaderTree(ibs []*IndexedBlock) (*HeaderTree, error) {
+	if len(ibs) == 0 {
+		return nil, ErrEmptyCache
+	}
+
+	t := &HeaderTree{nodes: make(map[chainhash.Hash]*headerNode, len(ibs))}
+	t.root = &headerNode{block: ibs[0], work: btcltypes.CalcHeaderWork(ibs[0].Header)}
+	t.nodes[ibs[0].BlockHash()] = t.root
+	t.best = []*headerNode{t.root}
+	for _, ib := range ibs[1:] {
+		parent, ok := t.nodes[ib.Header.PrevBlock]
+		if !ok || parent != t.best[len(t.best)-1] || ib.Height != parent.block.Height+1 {
+			return nil, fmt.Errorf("block %d (%s) does not extend the chain", ib.Height, ib.BlockHash())
+		}
+		node := t.newNode(ib, parent)
+		t.best = append(t.best, node)
+	}
+	return t, nil
+}
+
+func (t *HeaderTree) newNode(ib *IndexedBlock, parent *headerNode) *headerNode {
+	node := &headerNode{
+		block:  ib,
+		parent: parent,
+		work:   btcltypes.CumulativeWork(btcltypes.CalcHeaderWork(ib.Header), parent.work),
+	}
+	t.nodes[ib.BlockHash()] = node
+	return node
+}
+
+// Add inserts a block whose parent is in the tree, or reconnects a known block that was disconnected.
+// It returns whether the best chain changed, and ErrUnknownParent if the parent is not in the tree.
+func (t *HeaderTree) Add(ib *IndexedBlock) (bool, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if node, ok := t.nodes[ib.BlockHash()]; ok {
+		if !node.disconnected {
+			return false, nil
+		}
+		node.disconnected = false
+		return t.selectBest(), nil
+	}
+
+	parent, ok := t.nodes[ib.Header.PrevBlock]
+	if !ok {
+		return false, ErrUnknownParent
+	}
+	if ib.Height != parent.block.Height+1 {
+		return false, fmt.Errorf("block %s at height %d has parent at height %d", ib.BlockHash(), ib.Height, parent.block.Height)
+	}
+	node := t.newNode(ib, parent)
+
+	// a block extending the best tip is the common case, no need to search
+	if parent == t.best[len(t.best)-1] && t.usable(node) {
+		t.best = append(t.best, node)
+		return true, nil
+	}
+	return t.selectBest(), nil
+}
+
+// Disconnect marks a block as disconnected by the BTC node. The best chain falls back to the
+// branch with the most work among the remaining blocks. It returns whether the best chain changed.
+func (t *HeaderTree) Disconnect(hash *chainhash.Hash) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	node, ok := t.nodes[*hash]
+	if !ok || node.disconnected || node == t.root {
+		return false
+	}
+	node.disconnected = true
+	return t.selectBest()
+}
+
+// usable returns whether neither the node nor any of its ancestors is disconnected
+func (t *HeaderTree) usable(node *headerNode) bool {
+	for n := node; n != nil; n = n.parent {
+		if n.disconnected {
+			return false
+		}
+	}
+	return true
+}
+
+// selectBest picks the usable block with the most work as the best tip, keeping the current
+// tip on ties so that the first seen branch wins. It returns whether the best chain changed.
+func (t *HeaderTree) selectBest() bool {
+	current := t.best[len(t.best)-1]
+	tip := current
+	if !t.usable(tip) {
+		tip = t.root
+	}
+	for _, node := range t.nodes {
+		if node.work.GT(tip.work) && t.usable(node) {
+			tip = node
+		}
+	}
+	if tip == current {
+		return false
+	}
+
+	best := make([]*headerNode, tip.block.Height-t.root.block.Height+1)
+	for n := tip; n != nil; n = n.parent {
+		best[n.block.Height-t.root.block.Height] = n
+	}
+	t.best = best
+	return true
+}
+
+// Contains returns whether the block is in the tree
+func (t *HeaderTree) Contains(hash *chainhash.Hash) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	_, ok := t.nodes[*hash]
+	return ok
+}
+
+// Root returns the lowest block of the tree
+func (t *HeaderTree) Root() *IndexedBlock {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.root.block
+}
+
+// Tip returns the tip of the best chain
+func (t *HeaderTree) Tip() *IndexedBlock {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.best[len(t.best)-1].block
+}
+
+// BestAt returns the block of the best chain at the given height, or nil if the height is not in the tree
+func (t *HeaderTree) BestAt(height int32) *IndexedBlock {
+	t.RLock()
+	defer t.RUnlock()
+
+	idx := int(height - t.root.block.Height)
+	if idx < 0 || idx >= len(t.best) {
+		return nil
+	}
+	return t.best[idx].block
+}
+
+// OnBestChain returns whether the block is on the best chain. Blocks below the root are final
+// and considered on the best chain.
+func (t *HeaderTree) OnBestChain(ib *IndexedBlock) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	idx := int(ib.Height - t.root.block.Height)
+	if idx < 0 {
+		return true
+	}
+	return idx < len(t.best) && t.best[idx].block.BlockHash() == ib.BlockHash()
+}
+
+// BestChainFrom returns the blocks of the best chain from the given height to the tip
+func (t *HeaderTree) BestChainFrom(height int32) []*IndexedBlock {
+	t.RLock()
+	defer t.RUnlock()
+
+	idx := int(height - t.root.block.Height)
+	if idx < 0 {
+		idx = 0
+	}
+	ibs := make([]*IndexedBlock, 0, len(t.best))
+	for i := idx; i < len(t.best); i++ {
+		ibs = append(ibs, t.best[i].block)
+	}
+	return ibs
+}
+
+// Size returns the number of blocks in the tree
+func (t *HeaderTree) Size() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return len(t.nodes)
+}
+
+// Prune forgets the blocks more than depth blocks below the best tip, together with the branches forking off below them.
+func (t *HeaderTree) Prune(depth uint64) {
+	t.Lock()
+	defer t.Unlock()
+
+	if uint64(len(t.best)) <= depth+1 {
+		return
+	}
+	cut := len(t.best) - int(depth) - 1
+	newRoot := t.best[cut]
+
+	// keep the descendants of the new root only
+	kept := make(map[chainhash.Hash]*headerNode, len(t.nodes))
+	for hash, node := range t.nodes {
+		for n := node; n != nil; n = n.parent {
+			if n == newRoot {
+				kept[hash] = node
+				break
+			}
+			if n.block.Height < newRoot.block.Height {
+				break
+			}
+		}
+	}
+	newRoot.parent = nil
+	t.nodes = kept
+	t.root = newRoot
+	t.best = t.best[cut:]
+}
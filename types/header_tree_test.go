@@ -0,0 +1,69 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+
+	"github.com/Lorenzo-Protocol/lorenzo-relayer/v2/types"
+)
+
+// extend returns n blocks on top of parent with the given difficulty bits
+func extend(parent *types.IndexedBlock, n int, bits uint32, nonce uint32) []*types.IndexedBlock {
+	ibs := make([]*types.IndexedBlock, 0, n)
+	for i := 0; i < n; i++ {
+		prev := parent.BlockHash()
+		header := wire.NewBlockHeader(1, &prev, &chainhash.Hash{}, bits, nonce)
+		parent = types.NewIndexedBlock(parent.Height+1, header, nil)
+		ibs = append(ibs, parent)
+	}
+	return ibs
+}
+
+func TestHeaderTree(t *testing.T) {
+	genesis := types.NewIndexedBlock(100, wire.NewBlockHeader(1, &chainhash.Hash{}, &chainhash.Hash{}, 0x1d00ffff, 0), nil)
+	chain := append([]*types.IndexedBlock{genesis}, extend(genesis, 4, 0x1d00ffff, 1)...)
+	tree, err := types.NewHeaderTree(chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Tip() != chain[4] || tree.BestAt(102) != chain[2] || tree.BestAt(99) != nil {
+		t.Fatal("unexpected best chain after init")
+	}
+
+	// a shorter fork with more work becomes the best chain
+	fork := extend(chain[1], 2, 0x1c00ffff, 2)
+	for _, ib := range fork {
+		if _, err := tree.Add(ib); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tree.Tip() != fork[1] || tree.BestAt(102) != fork[0] || tree.OnBestChain(chain[3]) || !tree.OnBestChain(chain[1]) {
+		t.Fatal("expected the fork with more work to be the best chain")
+	}
+	if best := tree.BestChainFrom(102); len(best) != 2 || best[0] != fork[0] {
+		t.Fatalf("unexpected best chain from height 102: %v", best)
+	}
+
+	// disconnecting the fork falls back to the main chain
+	forkHash := fork[0].BlockHash()
+	if !tree.Disconnect(&forkHash) || tree.Tip() != chain[4] {
+		t.Fatal("expected the main chain to be the best chain after disconnecting the fork")
+	}
+	// and reconnecting it switches back
+	if changed, err := tree.Add(fork[0]); err != nil || !changed || tree.Tip() != fork[1] {
+		t.Fatal("expected the fork to be the best chain after reconnecting it")
+	}
+
+	orphan := extend(types.NewIndexedBlock(200, wire.NewBlockHeader(1, &chainhash.Hash{}, &chainhash.Hash{}, 0x1d00ffff, 9), nil), 1, 0x1d00ffff, 0)
+	if _, err := tree.Add(orphan[0]); err != types.ErrUnknownParent {
+		t.Fatalf("expected ErrUnknownParent, got %v", err)
+	}
+
+	// pruning drops the blocks below the new root and the branches forking off below it
+	tree.Prune(0)
+	if tree.Root() != fork[1] || tree.Size() != 1 || !tree.OnBestChain(chain[0]) {
+		t.Fatalf("unexpected tree after pruning, root %d, size %d", tree.Root().Height, tree.Size())
+	}
+}
@@ -1,8 +1,8 @@
 package types
 
 import (
-	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
 )
 
 type (
@@ -11,11 +11,12 @@ type (
 )
 
 const (
-	BtcMainnet SupportedBtcNetwork = "mainnet"
-	BtcTestnet SupportedBtcNetwork = "testnet"
-	BtcSimnet  SupportedBtcNetwork = "simnet"
-	BtcRegtest SupportedBtcNetwork = "regtest"
-	BtcSignet  SupportedBtcNetwork = "signet"
+	BtcMainnet  SupportedBtcNetwork = "mainnet"
+	BtcTestnet  SupportedBtcNetwork = "testnet"
+	BtcTestnet4 SupportedBtcNetwork = "testnet4"
+	BtcSimnet   SupportedBtcNetwork = "simnet"
+	BtcRegtest  SupportedBtcNetwork = "regtest"
+	BtcSignet   SupportedBtcNetwork = "signet"
 
 	Btcd     SupportedBtcBackend = "btcd"
 	Bitcoind SupportedBtcBackend = "bitcoind"
@@ -44,11 +45,12 @@ func GetWrappedTxs(msg *wire.MsgBlock) []*btcutil.Tx {
 
 func GetValidNetParams() map[string]bool {
 	params := map[string]bool{
-		BtcMainnet.String(): true,
-		BtcTestnet.String(): true,
-		BtcSimnet.String():  true,
-		BtcRegtest.String(): true,
-		BtcSignet.String():  true,
+		BtcMainnet.String():  true,
+		BtcTestnet.String():  true,
+		BtcTestnet4.String(): true,
+		BtcSimnet.String():   true,
+		BtcRegtest.String():  true,
+		BtcSignet.String():   true,
 	}
 
 	return params
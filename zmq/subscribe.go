@@ -30,7 +30,8 @@ type subscriptions struct {
 
 // SubscribeSequence subscribes to the ZMQ "sequence" messages as SequenceMsg items pushed onto the channel.
 // Call cancel to cancel the subscription and let the client release the resources. The channel is closed
-// when the subscription is canceled or when the client is closed.
+// when the subscription is canceled or when the client is closed. Subscribing again while the subscription
+// is active is a no-op, so that a restarted reporter can subscribe again.
 func (c *Client) SubscribeSequence() (err error) {
 	if c.zsub == nil {
 		err = ErrSubscribeDisabled
@@ -46,11 +47,12 @@ func (c *Client) SubscribeSequence() (err error) {
 	}
 
 	if c.subs.active {
-		err = ErrSubscriptionAlreadyActive
+		c.subs.Unlock()
 		return
 	}
 
 	if c.subs.zfront == nil {
+		c.subs.Unlock()
 		return errors.New("zfront is not initialized")
 	}
 	_, err = c.subs.zfront.SendMessage("subscribe", "sequence")
@@ -64,6 +66,26 @@ func (c *Client) SubscribeSequence() (err error) {
 	return
 }
 
+// Resubscribe reconnects to the ZMQ endpoint, for when the connection silently stopped delivering messages.
+// The subscription, if active, carries over to the new connection.
+func (c *Client) Resubscribe() error {
+	if c.zsub == nil {
+		return ErrSubscribeDisabled
+	}
+	c.subs.Lock()
+	defer c.subs.Unlock()
+	select {
+	case <-c.subs.exited:
+		return ErrSubscribeExited
+	default:
+	}
+	if c.subs.zfront == nil {
+		return errors.New("zfront is not initialized")
+	}
+	_, err := c.subs.zfront.SendMessage("reconnect")
+	return err
+}
+
 func (c *Client) zmqHandler() {
 	defer c.wg.Done()
 	defer func(zsub *zmq.Socket) {
@@ -125,6 +147,16 @@ OUTER:
 					if err := c.zsub.SetSubscribe(msg[1]); err != nil {
 						break OUTER
 					}
+				case "reconnect":
+					// the socket is only used by this goroutine, so it reconnects here
+					if err := c.zsub.Disconnect(c.zmqEndpoint); err != nil {
+						c.logger.Warnf("Failed to disconnect from ZMQ endpoint: %v", err)
+					}
+					if err := c.zsub.Connect(c.zmqEndpoint); err != nil {
+						c.logger.Errorf("Failed to reconnect to ZMQ endpoint: %v", err)
+						break OUTER
+					}
+					c.logger.Info("Reconnected to ZMQ endpoint")
 				case "term":
 					break OUTER
 				}